@@ -1,293 +1,322 @@
 // Package args offers a more Unix style options and arguments handling
 // alternative than the native "flags" implementation.
+//
+// The package level functions operate on a default Parser, named after the
+// running executable. Applications that need several independent sets of
+// definitions can create additional parsers with NewParser.
 package args
 
 import (
-	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strconv"
-	"strings"
-
-	"github.com/echsylon/go-args/internal/data"
-	"github.com/echsylon/go-args/internal/domain"
-	"github.com/echsylon/go-args/internal/util"
 )
 
-var state = domain.NewStateMachine(filepath.Base(os.Args[0]), "", data.NewRepository())
+var defaultParser = NewParser(filepath.Base(os.Args[0]), "")
 
-// SetApplicationDescription takes a human readable description of the app.
-// This text is only shown in the help output.
+// SetApplicationDescription sets the description of the default parser.
+// See Parser.SetApplicationDescription for details.
 func SetApplicationDescription(text string) {
-	state.SetDescription(text)
+	defaultParser.SetApplicationDescription(text)
 }
 
-// DefineOption allows the developer to define a simple optional command line
-// argument the caller can pass to the application. Only defined options will
-// be accepted during the parsing phase.
-//
-// If the name is a single character it will serve as the shortName, else as
-// the longName. The name is a mandatory field.
-//
-// The description, if given, is only shown in the help output.
-//
-// See DefineOptionStrict for more details.
+// SetOutput sets the output writers of the default parser.
+// See Parser.SetOutput for details.
+func SetOutput(stdout io.Writer, stderr io.Writer) {
+	defaultParser.SetOutput(stdout, stderr)
+}
+
+// SetExitFunction sets the exit function of the default parser.
+// See Parser.SetExitFunction for details.
+func SetExitFunction(exit func(code int)) {
+	defaultParser.SetExitFunction(exit)
+}
+
+// SetUsageExitCode sets the usage exit code of the default parser.
+// See Parser.SetUsageExitCode for details.
+func SetUsageExitCode(code int) {
+	defaultParser.SetUsageExitCode(code)
+}
+
+// SetConflictPolicy sets the boolean conflict policy of the default parser.
+// See Parser.SetConflictPolicy for details.
+func SetConflictPolicy(policy ConflictPolicy) {
+	defaultParser.SetConflictPolicy(policy)
+}
+
+// SetEnvironmentPrefix sets the environment variable prefix of the default
+// parser. See Parser.SetEnvironmentPrefix for details.
+func SetEnvironmentPrefix(prefix string) {
+	defaultParser.SetEnvironmentPrefix(prefix)
+}
+
+// SetConfigFile enables the config file source of the default parser.
+// See Parser.SetConfigFile for details.
+func SetConfigFile(optionName string, defaultPath string) {
+	defaultParser.SetConfigFile(optionName, defaultPath)
+}
+
+// SetResponseFilesEnabled toggles response file expansion on the default
+// parser. See Parser.SetResponseFilesEnabled for details.
+func SetResponseFilesEnabled(enabled bool) {
+	defaultParser.SetResponseFilesEnabled(enabled)
+}
+
+// AddCommand adds a command to the default parser.
+// See Parser.AddCommand for details.
+func AddCommand(name string, description string, aliases ...string) *Parser {
+	return defaultParser.AddCommand(name, description, aliases...)
+}
+
+// SetCommandAbbreviationsEnabled allows unique command prefixes on the default
+// parser. See Parser.SetCommandAbbreviationsEnabled for details.
+func SetCommandAbbreviationsEnabled(enabled bool) {
+	defaultParser.SetCommandAbbreviationsEnabled(enabled)
+}
+
+// SetOptionAbbreviationsEnabled allows unique long option prefixes on the
+// default parser. See Parser.SetOptionAbbreviationsEnabled for details.
+func SetOptionAbbreviationsEnabled(enabled bool) {
+	defaultParser.SetOptionAbbreviationsEnabled(enabled)
+}
+
+// SetPluginsEnabled turns on external plugin commands for the default parser.
+// See Parser.SetPluginsEnabled for details.
+func SetPluginsEnabled(enabled bool) {
+	defaultParser.SetPluginsEnabled(enabled)
+}
+
+// GetPlugin returns the plugin selected on the default parser.
+// See Parser.GetPlugin for details.
+func GetPlugin() (string, []string) {
+	return defaultParser.GetPlugin()
+}
+
+// SetPluginDirectories sets the plugin lookup directories of the default
+// parser. See Parser.SetPluginDirectories for details.
+func SetPluginDirectories(directories ...string) {
+	defaultParser.SetPluginDirectories(directories...)
+}
+
+// AddHelpCommand adds a help command to the default parser.
+// See Parser.AddHelpCommand for details.
+func AddHelpCommand(name string, description string) {
+	defaultParser.AddHelpCommand(name, description)
+}
+
+// AddHelpTopic adds a help topic to the default parser.
+// See Parser.AddHelpTopic for details.
+func AddHelpTopic(name string, description string, text string) {
+	defaultParser.AddHelpTopic(name, description, text)
+}
+
+// GetCommand returns the command selected on the default parser.
+// See Parser.GetCommand for details.
+func GetCommand() string {
+	return defaultParser.GetCommand()
+}
+
+// SetHandler registers the handler of the default parser.
+// See Parser.SetHandler for details.
+func SetHandler(handler Handler) {
+	defaultParser.SetHandler(handler)
+}
+
+// DefineOption defines a simple option on the default parser.
+// See Parser.DefineOption for details.
 func DefineOption(name string, description string) {
-	if len(name) > 1 {
-		DefineOptionStrict("", name, description, "")
-	} else {
-		DefineOptionStrict(name, "", description, "")
-	}
+	defaultParser.DefineOption(name, description)
 }
 
-// DefineOptionStrict allows the developer to define an optional command
-// line argument the caller can pass to the application.
-//
-// At least a shortName or a longName must be given. Defining both is nice
-// but not functionally required. The shortName must be a single alphabetic
-// character matching the `^[a-zA-Z]{1}$` regular expression, while the
-// longName must be at least two alphabetic+ characters, matching the
-// `^[a-zA-Z-._]{2,}$` regular expression.
-//
-// The description, if given, is only shown in the help output.
-//
-// If a pattern regular expression is given, then any caller provided value
-// for this option must match it for the value to be assigned to the option.
-// Empty string patterns are allowed and will behave as "accept everything".
-// The library will validate the pattern regular expression. If the validation
-// fails the library will panic runtime.
-//
-// If a caller passes the defined (short or long) option name alone, without
-// any corresponding value, the library will treat it as a boolean true flag
-// and return "true" for it's value.
-//
-// If the caller provides multiple instances of the same option, the library
-// will print a help text and exit the application gracefully.
-//
-// If the caller passes a value that doesn't match the given option pattern,
-// then the library will try to match the value for any argument instead. If
-// there is a defined argument that accepts the value it will be assigned to
-// that argument, otherwise the library will print a help text and exit the
-// application gracefully.
+// DefineOptionStrict defines an option on the default parser.
+// See Parser.DefineOptionStrict for details.
 func DefineOptionStrict(shortName string, longName string, description string, pattern string) {
-	err := state.DefineOption(shortName, longName, description, pattern)
-	if err != nil {
-		panic(err)
-	}
+	defaultParser.DefineOptionStrict(shortName, longName, description, pattern)
 }
 
-// DefineOptionHelp allows the devleoper to define a graceful help trigger
-// option.
-//
-// At least a shortName or a longName must be given. Defining both is nice
-// but not functionally required. The shortName must be a single alphabetic
-// character matching the `^[a-zA-Z]{1}$` regular expression, while the
-// longName must be at least two alphabetic+ characters, matching the
-// `^[a-zA-Z-._]{2,}$` regular expression.
-//
-// The description, if given, is only shown in the help output.
-//
-// If a caller passes this option, the library will immediately abort parsing
-// and print the help text - without any error message (!) - and exit the
-// application gracefully.
+// DefineOptionRepeatable defines a repeatable option on the default parser.
+// See Parser.DefineOptionRepeatable for details.
+func DefineOptionRepeatable(shortName string, longName string, description string, minCount int, maxCount int, pattern string) {
+	defaultParser.DefineOptionRepeatable(shortName, longName, description, minCount, maxCount, pattern)
+}
+
+// DefineOptionCounter defines a counter option on the default parser.
+// See Parser.DefineOptionCounter for details.
+func DefineOptionCounter(shortName string, longName string, description string) {
+	defaultParser.DefineOptionCounter(shortName, longName, description)
+}
+
+// DefineOptionBool defines a boolean option on the default parser.
+// See Parser.DefineOptionBool for details.
+func DefineOptionBool(shortName string, longName string, description string) {
+	defaultParser.DefineOptionBool(shortName, longName, description)
+}
+
+// DefineOptionHelp defines a help trigger option on the default parser.
+// See Parser.DefineOptionHelp for details.
 func DefineOptionHelp(shortName string, longName string, description string) {
-	err := state.DefineHelpOption(shortName, longName, description)
-	if err != nil {
-		panic(err)
-	}
+	defaultParser.DefineOptionHelp(shortName, longName, description)
 }
 
-// DefineArgument allows the developer to define a mandatory argument the
-// caller must pass to the application. By default the defined argument will
-// accept exactly one value of any shape and size.
-//
-// See DefineArgumentStrict for more details.
+// SetOptionDefaultValue sets an option default value on the default parser.
+// See Parser.SetOptionDefaultValue for details.
+func SetOptionDefaultValue(name string, value string) {
+	defaultParser.SetOptionDefaultValue(name, value)
+}
+
+// SetOptionRequired marks an option on the default parser as required.
+// See Parser.SetOptionRequired for details.
+func SetOptionRequired(name string) {
+	defaultParser.SetOptionRequired(name)
+}
+
+// SetOptionEnvironmentVariable binds an option on the default parser to an
+// environment variable. See Parser.SetOptionEnvironmentVariable for details.
+func SetOptionEnvironmentVariable(name string, variable string) {
+	defaultParser.SetOptionEnvironmentVariable(name, variable)
+}
+
+// DefineArgument defines a simple argument on the default parser.
+// See Parser.DefineArgument for details.
 func DefineArgument(name string, description string) {
-	DefineArgumentStrict(name, description, 1, 1, "")
+	defaultParser.DefineArgument(name, description)
 }
 
-// DefineArgumentStrict allows the developer to define more granular
-// mandatory arguments the caller must pass to the application.
-//
-// If minCount and maxCount is given the number of caller provided values will
-// be validated to be in that (inclusive) range. The minCount must be greater
-// than or equal to 1 and the maxCount must be greater than or equal to minCount.
-//
-// If a pattern is given, then the caller provided input argument value will be
-// matched against it. This allows the caller to mix the order of input values.
-// The values will in the parsing phase be associated with the first argument
-// definition that matches them. It is the developers responsibility to define
-// non-overlapping patterns (if this is important) and to provide sufficient
-// documentation in the argument descriptions for the caller to make an
-// educated call statement.
-//
-// If a pattern is given, it will be validated, causing the library to panic
-// runtime if it's invalid.
-//
-// If the caller fails to pass the constrained number of matching arguments,
-// the library will print a help text and exit the application gracefully.
-//
-// If the caller provides an argument value that doesn't match any defined
-// arguments, the library will print a help text and exit the application
-// gracefully.
+// DefineArgumentStrict defines an argument on the default parser.
+// See Parser.DefineArgumentStrict for details.
 func DefineArgumentStrict(name string, description string, minCount int, maxCount int, pattern string) {
-	err := state.DefineArgument(name, description, minCount, maxCount, pattern)
-	if err != nil {
-		panic(err)
-	}
+	defaultParser.DefineArgumentStrict(name, description, minCount, maxCount, pattern)
 }
 
-// Parse operates on the user provided command line arguments and matches them
-// against the developer defined option and argument configurations. The parse
-// function will validate the input and print the help text and exit gracefully
-// if:
-//
-//   - An unknown option is parsed.
-//
-//   - A value doesn't match any option or argument patterns.
-//
-//   - An argument value would violate the defined argument value count limits.
-//
-//   - After all input is parsed, there are defined mandatory arguments that
-//     hasn't received the minimum number of input values.
+// SetArgumentDefaultValues sets argument default values on the default
+// parser. See Parser.SetArgumentDefaultValues for details.
+func SetArgumentDefaultValues(name string, values ...string) {
+	defaultParser.SetArgumentDefaultValues(name, values...)
+}
+
+// SetArgumentEnvironmentVariable binds an argument on the default parser to
+// an environment variable. See Parser.SetArgumentEnvironmentVariable for
+// details.
+func SetArgumentEnvironmentVariable(name string, variable string) {
+	defaultParser.SetArgumentEnvironmentVariable(name, variable)
+}
+
+// Parse parses the command line arguments with the default parser.
+// See Parser.Parse for details.
 func Parse() {
-	if err := state.Parse(); err != nil {
-		exitWithHelpMessage(err, state)
-	}
+	defaultParser.Parse()
+}
+
+// TryParse parses the command line arguments with the default parser.
+// See Parser.TryParse for details.
+func TryParse() error {
+	return defaultParser.TryParse()
+}
+
+// Execute parses the command line arguments and runs the selected handler
+// of the default parser. See Parser.Execute for details.
+func Execute() {
+	defaultParser.Execute()
+}
+
+// Run parses the given input tokens and runs the selected handler of the
+// default parser. See Parser.Run for details.
+func Run(input []string) int {
+	return defaultParser.Run(input)
+}
+
+// ParseArgs parses the given input tokens with the default parser.
+// See Parser.ParseArgs for details.
+func ParseArgs(input []string) error {
+	return defaultParser.ParseArgs(input)
 }
 
-// GetOptionValue returns the parsed value for a defined option as a string.
-// If there is no parsed value for the option, the fallback is returned
-// instead.
+// GetOptionValue returns an option value from the default parser.
+// See Parser.GetOptionValue for details.
 func GetOptionValue(name string, fallback string) string {
-	result := state.GetOptionValue(name)
-	if result == "" {
-		result = fallback
-	}
-	return result
+	return defaultParser.GetOptionValue(name, fallback)
 }
 
-// GetOptionIntValue returns the parsed value for a defined option as a 64 bit
-// integer. If there is no parsed value for the option, the fallback is
-// returned instead.
+// GetOptionIntValue returns an option value from the default parser.
+// See Parser.GetOptionIntValue for details.
 func GetOptionIntValue(name string, fallback int64) int64 {
-	value := state.GetOptionValue(name)
-	result, err := strconv.ParseInt(value, 10, 64)
-	if err != nil {
-		result = fallback
-	}
-	return result
+	return defaultParser.GetOptionIntValue(name, fallback)
 }
 
-// GetOptionFloatValue returns the parsed value for a defined option as a
-// 64 bit floating point number. If there is no parsed value for the option,
-// the fallback is returned instead.
+// GetOptionFloatValue returns an option value from the default parser.
+// See Parser.GetOptionFloatValue for details.
 func GetOptionFloatValue(name string, fallback float64) float64 {
-	value := state.GetOptionValue(name)
-	result, err := strconv.ParseFloat(value, 64)
-	if err != nil {
-		result = fallback
-	}
-	return result
+	return defaultParser.GetOptionFloatValue(name, fallback)
 }
 
-// GetOptionBoolValue returns the parsed value for a defined option as a
-// boolean. If there is no parsed value for the option, the fallback is
-// returned instead.
+// GetOptionBoolValue returns an option value from the default parser.
+// See Parser.GetOptionBoolValue for details.
 func GetOptionBoolValue(name string, fallback bool) bool {
-	value := state.GetOptionValue(name)
-	result, err := strconv.ParseBool(value)
-	if err != nil {
-		result = fallback
-	}
-	return result
+	return defaultParser.GetOptionBoolValue(name, fallback)
 }
 
-// GetArgumentValues returns all parsed mandatory values that matched the
-// defined argument.
+// GetOptionValues returns all option values from the default parser.
+// See Parser.GetOptionValues for details.
+func GetOptionValues(name string) []string {
+	return defaultParser.GetOptionValues(name)
+}
+
+// GetOptionCount returns an option count from the default parser.
+// See Parser.GetOptionCount for details.
+func GetOptionCount(name string) int {
+	return defaultParser.GetOptionCount(name)
+}
+
+// GetArgumentValues returns the argument values from the default parser.
+// See Parser.GetArgumentValues for details.
 func GetArgumentValues(name string) []string {
-	return state.GetArgumentValues(name)
+	return defaultParser.GetArgumentValues(name)
 }
 
-// GetArgumentIntValues returns all parsed mandatory values that matched the
-// defined argument and can be cast into a 64 bit integer. Values that can not
-// be cast into a 64 bit integer are simply omitted from the result.
+// GetArgumentIntValues returns the argument values from the default parser.
+// See Parser.GetArgumentIntValues for details.
 func GetArgumentIntValues(name string) []int64 {
-	values := state.GetArgumentValues(name)
-	result := []int64{}
-	for _, value := range values {
-		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
-			result = append(result, number)
-		}
-	}
-	return result
-}
-
-// GetArgumentFloatValues returns all parsed mandatory values that matched the
-// defined argument and can be cast into a 64 bit floating point number. Values
-// that can not be cast into a 64 bit floating point number are simply omitted
-// from the result.
+	return defaultParser.GetArgumentIntValues(name)
+}
+
+// GetArgumentFloatValues returns the argument values from the default parser.
+// See Parser.GetArgumentFloatValues for details.
 func GetArgumentFloatValues(name string) []float64 {
-	values := state.GetArgumentValues(name)
-	result := []float64{}
-	for _, value := range values {
-		if number, err := strconv.ParseFloat(value, 64); err == nil {
-			result = append(result, number)
-		}
-	}
-	return result
-}
-
-// GetArgumentBoolValues returns all parsed mandatory values that matched the
-// defined argument and can be cast into a boolean value. Values that can not
-// be cast into a boolean value are simply omitted from the result.
+	return defaultParser.GetArgumentFloatValues(name)
+}
+
+// GetArgumentBoolValues returns the argument values from the default parser.
+// See Parser.GetArgumentBoolValues for details.
 func GetArgumentBoolValues(name string) []bool {
-	values := state.GetArgumentValues(name)
-	result := []bool{}
-	for _, value := range values {
-		if state, err := strconv.ParseBool(value); err == nil {
-			result = append(result, state)
-		}
-	}
-	return result
-}
-
-// Reset will delete all previously configured options and arguments and
-// purge any corresponding parsed values.
+	return defaultParser.GetArgumentBoolValues(name)
+}
+
+// GetOptionSource returns an option value source from the default parser.
+// See Parser.GetOptionSource for details.
+func GetOptionSource(name string) Source {
+	return defaultParser.GetOptionSource(name)
+}
+
+// GetOptionSources returns all option value sources from the default parser.
+// See Parser.GetOptionSources for details.
+func GetOptionSources(name string) []Source {
+	return defaultParser.GetOptionSources(name)
+}
+
+// GetArgumentSources returns the argument value sources from the default
+// parser. See Parser.GetArgumentSources for details.
+func GetArgumentSources(name string) []Source {
+	return defaultParser.GetArgumentSources(name)
+}
+
+// DumpValues describes all values of the default parser and their sources.
+// See Parser.DumpValues for details.
+func DumpValues() string {
+	return defaultParser.DumpValues()
+}
+
+// Reset resets the default parser.
+// See Parser.Reset for details.
 func Reset() {
-	state.Reset()
-}
-
-func exitWithHelpMessage(err error, state domain.StateMachine) {
-	var stringBuilder strings.Builder
-	var name = state.GetName()
-	var description = state.GetDescription()
-	var options = state.GetDefinedOptions()
-	var arguments = state.GetDefinedArguments()
-
-	var message = err.Error()
-	if message != "" {
-		stringBuilder.WriteString(message)
-		stringBuilder.WriteString("\n\n")
-	}
-
-	var mainSection = util.GetMainHelpSection(name, description, &options, &arguments)
-	if mainSection != "" {
-		stringBuilder.WriteString(mainSection)
-	}
-
-	var argumentsSection = util.GetArgumentsHelpSection(&arguments)
-	if argumentsSection != "" {
-		stringBuilder.WriteString("\n\n")
-		stringBuilder.WriteString(argumentsSection)
-	}
-
-	var optionsSection = util.GetOptionsHelpSection(&options)
-	if optionsSection != "" {
-		stringBuilder.WriteString("\n\n")
-		stringBuilder.WriteString(optionsSection)
-	}
-
-	fmt.Println(stringBuilder.String())
-	os.Exit(0)
+	defaultParser.Reset()
 }
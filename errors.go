@@ -0,0 +1,95 @@
+package args
+
+import "github.com/echsylon/go-args/internal/domain"
+
+// ParseError describes why the parsing of the caller provided input failed.
+//
+// The Kind field tells what went wrong. The Token field holds the offending
+// input token, if any, and the Index field its position in the parsed input
+// (not counting the application name), or -1 if the error isn't tied to a
+// specific token. The Definition field holds the name of the option or
+// argument definition the error relates to, if any. For MissingArgument
+// errors it holds a comma separated list of all unsatisfied arguments and
+// options. The Source field describes where the offending value came from
+// when it wasn't the command line, e.g. "environment variable APP_PORT" or
+// "app.json:3" for a config file and line. For input read from a response
+// file it holds the file and line of the offending token, while Index holds
+// the position of the `@file` token that included it. The Suggestion field
+// holds the defined option, argument, command or help topic name closest to
+// an unknown or unexpected token, if any is close enough, as the caller would
+// type it, e.g. "--verbose" for "--verbsoe". It's included in the message.
+type ParseError = domain.ParseError
+
+// ErrorKind classifies a ParseError.
+type ErrorKind = domain.ErrorKind
+
+const (
+	// UnknownOption is reported when the caller passes an option that
+	// hasn't been defined.
+	UnknownOption = domain.UnknownOption
+
+	// UnmatchedValue is reported when a value doesn't match any option or
+	// argument pattern.
+	UnmatchedValue = domain.UnmatchedValue
+
+	// TooManyValues is reported when an option is given more times than
+	// allowed, or when a value would exceed the maximum number of values
+	// for the argument it matches.
+	TooManyValues = domain.TooManyValues
+
+	// MissingArgument is reported when, after all input is parsed, there
+	// are arguments that haven't received their minimum number of values,
+	// or options that haven't been given their minimum number of times.
+	MissingArgument = domain.MissingArgument
+
+	// HelpRequested is reported when the caller passes a help option. It
+	// isn't a failure as such, but parsing is aborted nonetheless.
+	HelpRequested = domain.HelpRequested
+
+	// ConflictingValues is reported when the caller passes conflicting
+	// values for a boolean option and the RejectConflicts policy is used.
+	ConflictingValues = domain.ConflictingValues
+
+	// InvalidValue is reported when a value from a source other than the
+	// command line doesn't match the pattern of its definition.
+	InvalidValue = domain.InvalidValue
+
+	// InvalidConfig is reported when a config file can't be read or isn't
+	// well formed.
+	InvalidConfig = domain.InvalidConfig
+
+	// InvalidResponseFile is reported when a response file can't be read,
+	// isn't well formed or includes itself.
+	InvalidResponseFile = domain.InvalidResponseFile
+
+	// UnknownCommand is reported when the caller selects a command that
+	// hasn't been defined.
+	UnknownCommand = domain.UnknownCommand
+
+	// MissingCommand is reported when the parser has commands, but the
+	// caller didn't select any. The Definition field holds a comma separated
+	// list of the available commands.
+	MissingCommand = domain.MissingCommand
+
+	// AmbiguousCommand is reported when command abbreviations are enabled and
+	// the caller gives a prefix of more than one command. The Definition field
+	// holds a comma separated list of the matching commands.
+	AmbiguousCommand = domain.AmbiguousCommand
+
+	// AmbiguousOption is reported when option abbreviations are enabled and
+	// the caller gives a prefix of more than one long option name. The
+	// Definition field holds a comma separated list of the matching names.
+	AmbiguousOption = domain.AmbiguousOption
+)
+
+// ConflictPolicy decides how conflicting boolean option values are handled.
+type ConflictPolicy = domain.ConflictPolicy
+
+const (
+	// LastWins makes the last given value of a boolean option win.
+	LastWins = domain.LastWins
+
+	// RejectConflicts makes parsing fail when a boolean option is given
+	// conflicting values.
+	RejectConflicts = domain.RejectConflicts
+)
@@ -0,0 +1,79 @@
+package args
+
+import (
+	"errors"
+	"strings"
+)
+
+// Handler runs the business logic of a command. It's registered with
+// SetHandler and called by Run and Execute once the input is parsed.
+type Handler func(ctx Context) error
+
+// Context is passed to a Handler. It embeds the parser of the selected
+// command, so the handler can read the parsed values directly, including
+// values of options inherited from parent parsers, e.g.
+// `ctx.GetOptionValue("output", "")`.
+type Context struct {
+	*Parser
+
+	// Command holds the names of the selected commands, separated by a
+	// space, e.g. "remote add". It's empty if no command was selected.
+	Command string
+}
+
+// ExitCoder is implemented by errors that decide the exit status of the
+// application when returned from a Handler.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// WithExitCode wraps an error so that Run and Execute exit with the given
+// status when a Handler returns it. The wrapped error is still reachable
+// through errors.Unwrap, errors.Is and errors.As.
+func WithExitCode(err error, code int) error {
+	return &exitError{err: err, code: code}
+}
+
+// DefaultErrorExitCode is the exit status Run uses when a Handler returns an
+// error that doesn't implement ExitCoder.
+const DefaultErrorExitCode = 1
+
+type exitError struct {
+	err  error
+	code int
+}
+
+func (err *exitError) Error() string {
+	var result = ""
+	if err.err != nil {
+		result = err.err.Error()
+	}
+	return result
+}
+
+func (err *exitError) Unwrap() error {
+	return err.err
+}
+
+func (err *exitError) ExitCode() int {
+	return err.code
+}
+
+func getExitCode(err error) int {
+	var result = 0
+	var exitCoder ExitCoder
+	if errors.As(err, &exitCoder) {
+		result = exitCoder.ExitCode()
+	} else if err != nil {
+		result = DefaultErrorExitCode
+	}
+	return result
+}
+
+func newContext(parser *Parser) Context {
+	var names []string
+	for command := parser; command != command.root; command = command.parent {
+		names = append([]string{command.state.GetName()}, names...)
+	}
+	return Context{Parser: parser, Command: strings.Join(names, " ")}
+}
@@ -7,3 +7,11 @@ var ArgumentNamePattern = regexp.MustCompile(`^[a-zA-Z0-9-._]+$`)
 var OptionShortNamePattern = regexp.MustCompile(`^[a-zA-Z]{1}$`)
 var OptionLongNamePattern = regexp.MustCompile(`^[a-zA-Z-._]{2,}$`)
 var OptionNamePattern = regexp.MustCompile(`^(-[a-zA-Z]{1}$ | --[a-zA-Z-._]{2,})$`)
+var CommandNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-._]*$`)
+var EnvironmentVariablePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+var NegativeNumberPattern = regexp.MustCompile(`^-\d+(\.\d+)?$`)
+
+const EndOfOptionsToken = "--"
+const NegatedOptionPrefix = "no-"
+const BooleanValuePattern = `^(true|false)$`
+const UnboundedCount = int(^uint(0) >> 1)
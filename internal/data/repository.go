@@ -8,16 +8,24 @@ type Repository interface {
 	ClearAll()
 	ClearValues()
 	SaveOption(shortName string, longName string, description string, pattern string)
+	SaveRepeatableOption(shortName string, longName string, description string, min int, max int, pattern string)
+	SaveCounterOption(shortName string, longName string, description string)
+	SaveBooleanOption(shortName string, longName string, description string)
 	SaveHelpOption(shortName string, longName string, description string)
 	GetOptions() []model.Option
 	GetOption(name string) model.Option
 	SaveOptionValue(name string, value string)
+	SaveOptionSource(name string, source model.Source)
+	GetOptionSources(name string) []model.Source
 	GetOptionValue(name string) string
+	GetOptionValues(name string) []string
 	SaveArgument(name string, description string, min int, max int, pattern string)
 	GetArguments() []model.Argument
 	GetArgument(name string) model.Argument
 	SaveArgumentValue(name string, value string)
 	GetArgumentValues(name string) []string
+	SaveArgumentSource(name string, source model.Source)
+	GetArgumentSources(name string) []model.Source
 }
 
 type any = interface{}
@@ -25,7 +33,8 @@ type any = interface{}
 func NewRepository() Repository {
 	return &repository{
 		definitions: []any{},
-		values:      make(map[any][]string)}
+		values:      make(map[any][]string),
+		sources:     make(map[any][]model.Source)}
 }
 
 type repository struct {
@@ -38,21 +47,39 @@ type repository struct {
 	// order dependencies.
 	definitions []any
 	values      map[any][]string
+	sources     map[any][]model.Source
 }
 
 func (cache *repository) ClearAll() {
 	cache.definitions = []any{}
 	cache.values = make(map[any][]string)
+	cache.sources = make(map[any][]model.Source)
 }
 
 func (cache *repository) ClearValues() {
 	cache.values = make(map[any][]string)
+	cache.sources = make(map[any][]model.Source)
+	for _, option := range cache.GetOptions() {
+		option.ClearParsed()
+	}
 }
 
 func (cache *repository) SaveOption(shortName string, longName string, description string, pattern string) {
 	cache.definitions = append(cache.definitions, model.NewOption(shortName, longName, description, pattern))
 }
 
+func (cache *repository) SaveRepeatableOption(shortName string, longName string, description string, min int, max int, pattern string) {
+	cache.definitions = append(cache.definitions, model.NewRepeatableOption(shortName, longName, description, min, max, pattern))
+}
+
+func (cache *repository) SaveCounterOption(shortName string, longName string, description string) {
+	cache.definitions = append(cache.definitions, model.NewCounterOption(shortName, longName, description))
+}
+
+func (cache *repository) SaveBooleanOption(shortName string, longName string, description string) {
+	cache.definitions = append(cache.definitions, model.NewBooleanOption(shortName, longName, description))
+}
+
 func (cache *repository) SaveHelpOption(shortName string, longName string, description string) {
 	cache.definitions = append(cache.definitions, model.NewHelpOption(shortName, longName, description))
 }
@@ -73,22 +100,45 @@ func (cache *repository) GetOption(name string) model.Option {
 
 func (cache *repository) SaveOptionValue(name string, value string) {
 	if option := findOption(name, name, &cache.definitions); option != nil {
-		cache.values[option] = []string{value}
+		cache.values[option] = append(cache.values[option], value)
 	}
 }
 
 func (cache *repository) GetOptionValue(name string) string {
 	var result = ""
+	values := cache.GetOptionValues(name)
+	if count := len(values); count > 0 {
+		result = values[count-1]
+	}
+	return result
+}
+
+func (cache *repository) GetOptionValues(name string) []string {
+	var result []string
 	option := findOption(name, name, &cache.definitions)
 	if option != nil {
-		values, hasValue := cache.values[option]
-		if hasValue {
-			result = values[0]
+		values, hasValues := cache.values[option]
+		if hasValues {
+			result = values
 		}
 	}
 	return result
 }
 
+func (cache *repository) SaveOptionSource(name string, source model.Source) {
+	if option := findOption(name, name, &cache.definitions); option != nil {
+		cache.sources[option] = append(cache.sources[option], source)
+	}
+}
+
+func (cache *repository) GetOptionSources(name string) []model.Source {
+	var result []model.Source
+	if option := findOption(name, name, &cache.definitions); option != nil {
+		result = cache.sources[option]
+	}
+	return result
+}
+
 func (cache *repository) SaveArgument(name string, description string, min int, max int, pattern string) {
 	cache.definitions = append(cache.definitions, model.NewArgument(name, description, min, max, pattern))
 }
@@ -131,6 +181,20 @@ func (cache *repository) GetArgumentValues(name string) []string {
 	return result
 }
 
+func (cache *repository) SaveArgumentSource(name string, source model.Source) {
+	if argument := findArgument(name, &cache.definitions); argument != nil {
+		cache.sources[argument] = append(cache.sources[argument], source)
+	}
+}
+
+func (cache *repository) GetArgumentSources(name string) []model.Source {
+	var result []model.Source
+	if argument := findArgument(name, &cache.definitions); argument != nil {
+		result = cache.sources[argument]
+	}
+	return result
+}
+
 func findOption(shortName string, longName string, definitions *[]any) model.Option {
 	var result model.Option = nil
 	if shortName != "" || longName != "" {
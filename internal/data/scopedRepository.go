@@ -0,0 +1,112 @@
+package data
+
+import (
+	"github.com/echsylon/go-args/internal/model"
+)
+
+// NewScopedRepository creates a repository for a command. Options defined in
+// the parent repository are inherited and visible through the new repository,
+// while arguments are local to it. Option values are always saved in the
+// repository that owns the option definition.
+func NewScopedRepository(parent Repository) Repository {
+	return &scopedRepository{
+		local:  NewRepository(),
+		parent: parent}
+}
+
+type scopedRepository struct {
+	local  Repository
+	parent Repository
+}
+
+func (scope *scopedRepository) ClearAll() {
+	scope.local.ClearAll()
+}
+
+func (scope *scopedRepository) ClearValues() {
+	scope.local.ClearValues()
+}
+
+func (scope *scopedRepository) SaveOption(shortName string, longName string, description string, pattern string) {
+	scope.local.SaveOption(shortName, longName, description, pattern)
+}
+
+func (scope *scopedRepository) SaveRepeatableOption(shortName string, longName string, description string, min int, max int, pattern string) {
+	scope.local.SaveRepeatableOption(shortName, longName, description, min, max, pattern)
+}
+
+func (scope *scopedRepository) SaveCounterOption(shortName string, longName string, description string) {
+	scope.local.SaveCounterOption(shortName, longName, description)
+}
+
+func (scope *scopedRepository) SaveBooleanOption(shortName string, longName string, description string) {
+	scope.local.SaveBooleanOption(shortName, longName, description)
+}
+
+func (scope *scopedRepository) SaveHelpOption(shortName string, longName string, description string) {
+	scope.local.SaveHelpOption(shortName, longName, description)
+}
+
+func (scope *scopedRepository) GetOptions() []model.Option {
+	return append(scope.local.GetOptions(), scope.parent.GetOptions()...)
+}
+
+func (scope *scopedRepository) GetOption(name string) model.Option {
+	return scope.getOptionOwner(name).GetOption(name)
+}
+
+func (scope *scopedRepository) SaveOptionValue(name string, value string) {
+	scope.getOptionOwner(name).SaveOptionValue(name, value)
+}
+
+func (scope *scopedRepository) GetOptionValue(name string) string {
+	return scope.getOptionOwner(name).GetOptionValue(name)
+}
+
+func (scope *scopedRepository) GetOptionValues(name string) []string {
+	return scope.getOptionOwner(name).GetOptionValues(name)
+}
+
+func (scope *scopedRepository) SaveOptionSource(name string, source model.Source) {
+	scope.getOptionOwner(name).SaveOptionSource(name, source)
+}
+
+func (scope *scopedRepository) GetOptionSources(name string) []model.Source {
+	return scope.getOptionOwner(name).GetOptionSources(name)
+}
+
+func (scope *scopedRepository) SaveArgument(name string, description string, min int, max int, pattern string) {
+	scope.local.SaveArgument(name, description, min, max, pattern)
+}
+
+func (scope *scopedRepository) GetArguments() []model.Argument {
+	return scope.local.GetArguments()
+}
+
+func (scope *scopedRepository) GetArgument(name string) model.Argument {
+	return scope.local.GetArgument(name)
+}
+
+func (scope *scopedRepository) SaveArgumentValue(name string, value string) {
+	scope.local.SaveArgumentValue(name, value)
+}
+
+func (scope *scopedRepository) GetArgumentValues(name string) []string {
+	return scope.local.GetArgumentValues(name)
+}
+
+func (scope *scopedRepository) SaveArgumentSource(name string, source model.Source) {
+	scope.local.SaveArgumentSource(name, source)
+}
+
+func (scope *scopedRepository) GetArgumentSources(name string) []model.Source {
+	return scope.local.GetArgumentSources(name)
+}
+
+func (scope *scopedRepository) getOptionOwner(name string) Repository {
+	var result = scope.local
+	if scope.local.GetOption(name) == nil && scope.parent.GetOption(name) != nil {
+		result = scope.parent
+	}
+	return result
+}
@@ -0,0 +1,323 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/echsylon/go-args/internal/model"
+	"github.com/echsylon/go-args/internal/source"
+)
+
+// argumentMatcher assigns positional values to arguments as a bipartite
+// matching where each value goes to one argument whose pattern it matches
+// and each argument takes at most its capacity of values. The matching grows
+// one augmenting path at a time (Kuhn's algorithm), which runs in O(V*E) for
+// V values and E matching value and argument pairs. Growing the matching
+// never lowers the number of values an argument holds, so a min count stays
+// met once it's met.
+type argumentMatcher struct {
+	matches    [][]bool
+	capacities []int
+	assigned   [][]int
+	owners     []int
+}
+
+func newArgumentMatcher(matches [][]bool, capacities []int) *argumentMatcher {
+	owners := make([]int, len(matches))
+	for position := range owners {
+		owners[position] = -1
+	}
+
+	return &argumentMatcher{
+		matches:    matches,
+		capacities: capacities,
+		assigned:   make([][]int, len(capacities)),
+		owners:     owners,
+	}
+}
+
+func (matcher *argumentMatcher) assign(position int) bool {
+	return matcher.augment(position, make([]bool, len(matcher.capacities)))
+}
+
+func (matcher *argumentMatcher) augment(position int, visited []bool) bool {
+	var result = false
+	for index := 0; !result && index < len(matcher.capacities); index++ {
+		if matcher.matches[position][index] && !visited[index] {
+			visited[index] = true
+			result = len(matcher.assigned[index]) < matcher.capacities[index] || matcher.reassignAny(index, visited)
+			if result {
+				matcher.assigned[index] = append(matcher.assigned[index], position)
+				matcher.owners[position] = index
+			}
+		}
+	}
+	return result
+}
+
+// reassignAny moves one of the values of the argument to another argument,
+// making room for a new value.
+func (matcher *argumentMatcher) reassignAny(index int, visited []bool) bool {
+	var result = false
+	var values = matcher.assigned[index]
+	for offset, position := range values {
+		if result = matcher.augment(position, visited); result {
+			matcher.assigned[index] = append(append([]int{}, values[:offset]...), values[offset+1:]...)
+			break
+		}
+	}
+	return result
+}
+
+// assignFrom tries to assign each unassigned value from the given position
+// on, in order, and returns the number of values it couldn't assign.
+func (matcher *argumentMatcher) assignFrom(first int) int {
+	var result = 0
+	for position := first; position < len(matcher.matches); position++ {
+		if matcher.owners[position] < 0 && !matcher.assign(position) {
+			result++
+		}
+	}
+	return result
+}
+
+func (matcher *argumentMatcher) getAssignedCount() int {
+	var result = 0
+	for _, values := range matcher.assigned {
+		result += len(values)
+	}
+	return result
+}
+
+// findFirstUnassignableValue returns the position of the first value that
+// can't be assigned together with the values before it, or -1 if all values
+// can be assigned.
+func findFirstUnassignableValue(matches [][]bool, spare []int) int {
+	var result = -1
+	matcher := newArgumentMatcher(matches, append([]int{}, spare...))
+	for position := range matches {
+		if !matcher.assign(position) {
+			result = position
+			break
+		}
+	}
+	return result
+}
+
+// findReachableMinimums enforces the min counts one argument at a time, in
+// definition order, and returns the number of values each argument can be
+// guaranteed without giving up the guarantees of the arguments before it.
+func findReachableMinimums(matches [][]bool, deficits []int) []int {
+	var result = make([]int, len(deficits))
+	matcher := newArgumentMatcher(matches, make([]int, len(deficits)))
+	for index, deficit := range deficits {
+		matcher.capacities[index] = deficit
+		for position := 0; position < len(matches) && len(matcher.assigned[index]) < deficit; position++ {
+			if matcher.owners[position] < 0 {
+				matcher.assign(position)
+			}
+		}
+	}
+
+	for index, values := range matcher.assigned {
+		result[index] = len(values)
+	}
+	return result
+}
+
+// findValidAssignment returns any assignment giving each argument at least
+// its required number of values and at most its spare capacity.
+func findValidAssignment(matches [][]bool, required []int, spare []int) []int {
+	matcher := newArgumentMatcher(matches, append([]int{}, required...))
+	matcher.assignFrom(0)
+	matcher.capacities = spare
+	matcher.assignFrom(0)
+	return matcher.owners
+}
+
+// assignArgumentValues saves the positional values given on the command
+// line to the arguments, see argumentMatcher. If the values overflow the
+// arguments, the first value that can't be assigned is reported. If the min
+// counts can't all be met, they are enforced one argument at a time, in
+// definition order, and the arguments left short are reported as missing
+// once environment variables, config files and default values have had
+// their say. Among the valid assignments the earliest one is picked, see
+// findEarliestAssignment. That is the one a greedy matcher would pick,
+// whenever the greedy one is valid.
+func (state *stateMachine) assignArgumentValues(values []positionalValue) *ParseError {
+	var result *ParseError = nil
+	var arguments = state.data.GetArguments()
+	var matches = make([][]bool, len(values))
+	var spare []int
+	var deficits []int
+	for position, value := range values {
+		matches[position] = make([]bool, len(arguments))
+		for index, argument := range arguments {
+			matches[position][index] = isValidValue(argument.GetPattern(), value.token.Value)
+		}
+	}
+	for _, argument := range arguments {
+		count := len(state.data.GetArgumentValues(argument.GetName()))
+		spare = append(spare, maxInt(0, argument.GetMaxValuesCount()-count))
+		deficits = append(deficits, maxInt(0, argument.GetMinValuesCount()-count))
+	}
+
+	if position := findFirstUnassignableValue(matches, spare); position >= 0 {
+		result = newTooManyArgumentValuesError(values[position], arguments, matches[position])
+	} else {
+		required := findReachableMinimums(matches, deficits)
+		assignment := findEarliestAssignment(matches, required, spare)
+		for position, value := range values {
+			state.saveArgumentValue(arguments[assignment[position]].GetName(), value.token.Value, value.origin)
+		}
+	}
+	return result
+}
+
+// findEarliestAssignment returns the valid assignment that gives each value,
+// in input order, to the earliest argument possible. Starting from any valid
+// assignment, each value is moved to the earliest argument it can be moved
+// to, see assignmentRerouter. The values before it are left untouched.
+func findEarliestAssignment(matches [][]bool, required []int, spare []int) []int {
+	rerouter := newAssignmentRerouter(matches, required, spare)
+	for position := range matches {
+		for index := 0; index < rerouter.assignment[position]; index++ {
+			if matches[position][index] && rerouter.move(position, index) {
+				break
+			}
+		}
+	}
+	return rerouter.assignment
+}
+
+// assignmentRerouter moves values between arguments in a valid assignment.
+// Moving a value leaves at most one value too many on its new argument and
+// one too few on its old one. Both are fixed by moving the values after it
+// along one path: either from the new argument to the old one, or from the
+// new argument to one with room to spare and from one with values to spare
+// to the old argument. The arguments with room or values to spare are joined
+// through a pool node, so one breadth first search finds either path. The
+// latest values are moved first, which keeps the common cases linear, like
+// many source values followed by a single destination value.
+type assignmentRerouter struct {
+	matches    [][]bool
+	required   []int
+	spare      []int
+	assignment []int
+	counts     []int
+}
+
+func newAssignmentRerouter(matches [][]bool, required []int, spare []int) *assignmentRerouter {
+	assignment := findValidAssignment(matches, required, spare)
+	counts := make([]int, len(spare))
+	for _, owner := range assignment {
+		counts[owner]++
+	}
+
+	return &assignmentRerouter{
+		matches:    matches,
+		required:   required,
+		spare:      spare,
+		assignment: assignment,
+		counts:     counts,
+	}
+}
+
+// move moves the value at the position to the argument at the index if the
+// values after it can be rerouted to keep the assignment valid.
+func (rerouter *assignmentRerouter) move(position int, index int) bool {
+	var pool = len(rerouter.spare)
+	var owner = rerouter.assignment[position]
+	var start = pool
+	var goal = pool
+	rerouter.counts[owner]--
+	rerouter.counts[index]++
+	if rerouter.counts[index] > rerouter.spare[index] {
+		start = index
+	}
+	if rerouter.counts[owner] < rerouter.required[owner] {
+		goal = owner
+	}
+
+	result := start == goal || rerouter.reroute(position, start, goal)
+	if result {
+		rerouter.assignment[position] = index
+	} else {
+		rerouter.counts[owner]++
+		rerouter.counts[index]--
+	}
+	return result
+}
+
+// reroute searches a path from the start node to the goal node, moving only
+// values after the position, and moves the values along it if found.
+func (rerouter *assignmentRerouter) reroute(position int, start int, goal int) bool {
+	var pool = len(rerouter.spare)
+	var parents = make([]int, pool+1)
+	var movers = make([]int, pool+1)
+	var queue = []int{start}
+	for node := range parents {
+		parents[node] = -1
+	}
+	parents[start] = start
+
+	for len(queue) > 0 && parents[goal] < 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if node == pool {
+			for index := 0; index < pool; index++ {
+				if parents[index] < 0 && rerouter.counts[index] > rerouter.required[index] {
+					parents[index], movers[index] = pool, -1
+					queue = append(queue, index)
+				}
+			}
+		} else {
+			if parents[pool] < 0 && rerouter.counts[node] < rerouter.spare[node] {
+				parents[pool], movers[pool] = node, -1
+				queue = append(queue, pool)
+			}
+			for value := len(rerouter.matches) - 1; parents[goal] < 0 && value > position; value-- {
+				if rerouter.assignment[value] == node {
+					for index := 0; index < pool; index++ {
+						if parents[index] < 0 && rerouter.matches[value][index] {
+							parents[index], movers[index] = node, value
+							queue = append(queue, index)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	result := parents[goal] >= 0
+	for node := goal; result && node != start; node = parents[node] {
+		if value := movers[node]; value >= 0 {
+			rerouter.assignment[value] = node
+			rerouter.counts[parents[node]]--
+			rerouter.counts[node]++
+		}
+	}
+	return result
+}
+
+type positionalValue struct {
+	token  source.Token
+	origin model.Source
+}
+
+func newTooManyArgumentValuesError(value positionalValue, arguments []model.Argument, matches []bool) *ParseError {
+	var names []string
+	for index, argument := range arguments {
+		if matches[index] {
+			names = append(names, argument.GetName())
+		}
+	}
+	return &ParseError{Kind: TooManyValues, Token: value.token.Value, Index: value.token.Index, Definition: strings.Join(names, ", "), Source: getTokenLocation(value.token)}
+}
+
+func maxInt(first int, second int) int {
+	var result = first
+	if second > first {
+		result = second
+	}
+	return result
+}
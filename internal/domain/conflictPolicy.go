@@ -0,0 +1,8 @@
+package domain
+
+type ConflictPolicy int
+
+const (
+	LastWins ConflictPolicy = iota
+	RejectConflicts
+)
@@ -0,0 +1,106 @@
+package domain
+
+import "fmt"
+
+type ErrorKind int
+
+const (
+	UnknownOption ErrorKind = iota + 1
+	UnmatchedValue
+	TooManyValues
+	MissingArgument
+	HelpRequested
+	ConflictingValues
+	InvalidValue
+	InvalidConfig
+	InvalidResponseFile
+	UnknownCommand
+	MissingCommand
+	AmbiguousCommand
+	AmbiguousOption
+)
+
+type ParseError struct {
+	Kind       ErrorKind
+	Token      string
+	Index      int
+	Definition string
+	Source     string
+	Suggestion string
+}
+
+func (kind ErrorKind) String() string {
+	switch kind {
+	case UnknownOption:
+		return "UnknownOption"
+	case UnmatchedValue:
+		return "UnmatchedValue"
+	case TooManyValues:
+		return "TooManyValues"
+	case MissingArgument:
+		return "MissingArgument"
+	case HelpRequested:
+		return "HelpRequested"
+	case ConflictingValues:
+		return "ConflictingValues"
+	case InvalidValue:
+		return "InvalidValue"
+	case InvalidConfig:
+		return "InvalidConfig"
+	case InvalidResponseFile:
+		return "InvalidResponseFile"
+	case UnknownCommand:
+		return "UnknownCommand"
+	case MissingCommand:
+		return "MissingCommand"
+	case AmbiguousCommand:
+		return "AmbiguousCommand"
+	case AmbiguousOption:
+		return "AmbiguousOption"
+	default:
+		return fmt.Sprintf("ErrorKind(%d)", int(kind))
+	}
+}
+
+func (err *ParseError) Error() string {
+	var result string
+	switch err.Kind {
+	case UnknownOption:
+		result = fmt.Sprintf("unknown option: %s", err.Token)
+	case UnmatchedValue:
+		result = fmt.Sprintf("unexpected input: %s", err.Token)
+	case TooManyValues:
+		result = fmt.Sprintf("too many values for %s: %s", err.Definition, err.Token)
+	case MissingArgument:
+		result = fmt.Sprintf("missing input for: %s", err.Definition)
+	case HelpRequested:
+		result = ""
+	case ConflictingValues:
+		result = fmt.Sprintf("conflicting values for %s: %s", err.Definition, err.Token)
+	case InvalidValue:
+		result = fmt.Sprintf("invalid value for %s: %s", err.Definition, err.Token)
+	case InvalidConfig:
+		result = fmt.Sprintf("invalid config file: %s", err.Token)
+	case InvalidResponseFile:
+		result = fmt.Sprintf("invalid response file: %s", err.Token)
+	case UnknownCommand:
+		result = fmt.Sprintf("unknown command: %s", err.Token)
+	case MissingCommand:
+		result = fmt.Sprintf("missing command, expected one of: %s", err.Definition)
+	case AmbiguousCommand:
+		result = fmt.Sprintf("ambiguous command: %s (%s)", err.Token, err.Definition)
+	case AmbiguousOption:
+		result = fmt.Sprintf("ambiguous option: %s (%s)", err.Token, err.Definition)
+	default:
+		result = fmt.Sprintf("unexpected input: %s", err.Token)
+	}
+
+	if err.Suggestion != "" && result != "" {
+		result = fmt.Sprintf("%s (did you mean %s?)", result, err.Suggestion)
+	}
+
+	if err.Source != "" && result != "" {
+		result = err.Source + ": " + result
+	}
+	return result
+}
@@ -9,32 +9,90 @@ import (
 	"github.com/echsylon/go-args/internal/configuration"
 	"github.com/echsylon/go-args/internal/data"
 	"github.com/echsylon/go-args/internal/model"
+	"github.com/echsylon/go-args/internal/source"
 )
 
 type StateMachine interface {
 	SetName(name string)
 	GetName() string
+	GetPath() string
 	SetDescription(description string)
 	GetDescription() string
+	AddCommand(name string, description string) (StateMachine, error)
+	AddAlias(alias string) error
+	GetAliases() []string
+	AddHelpCommand(name string, description string) error
+	AddHelpTopic(name string, description string, text string) error
+	GetDefinedHelpTopics() []model.Topic
+	GetHelpTopic(name string) model.Topic
+	GetDefinedCommands() []model.Command
+	GetSelectedCommand() StateMachine
+	SetConflictPolicy(policy ConflictPolicy)
+	SetEnvironmentPrefix(prefix string)
+	SetConfigFile(optionName string, path string) error
+	SetResponseFilesEnabled(enabled bool)
+	SetCommandAbbreviationsEnabled(enabled bool)
+	SetOptionAbbreviationsEnabled(enabled bool)
+	SetPluginsEnabled(enabled bool)
+	SetPluginDirectories(directories []string)
+	GetDefinedPlugins() []model.Plugin
+	GetSelectedPlugin() (model.Plugin, []string)
 	DefineOption(shortName string, longName string, description string, pattern string) error
+	DefineRepeatableOption(shortName string, longName string, description string, minCount int, maxCount int, pattern string) error
+	DefineCounterOption(shortName string, longName string, description string) error
+	DefineBooleanOption(shortName string, longName string, description string) error
 	DefineHelpOption(shortName string, longName string, description string) error
 	GetDefinedOptions() []model.Option
 	GetOptionValue(name string) string
+	GetOptionValues(name string) []string
+	GetOptionCount(name string) int
+	GetOptionSources(name string) []model.Source
+	SetOptionDefaultValue(name string, value string) error
+	SetOptionRequired(name string) error
+	SetOptionEnvironmentVariable(name string, variable string) error
 	DefineArgument(name string, description string, minCount int, maxCount int, pattern string) error
 	GetDefinedArguments() []model.Argument
 	GetArgumentValues(name string) []string
+	GetArgumentSources(name string) []model.Source
+	SetArgumentDefaultValues(name string, values []string) error
+	SetArgumentEnvironmentVariable(name string, variable string) error
 	Parse() error
+	ParseArgs(input []string) error
 	Reset()
 }
 
 func NewStateMachine(name string, description string, data data.Repository) StateMachine {
-	return &stateMachine{name, description, data}
+	return &stateMachine{
+		name:           name,
+		description:    description,
+		data:           data,
+		conflictPolicy: LastWins,
+	}
 }
 
 type stateMachine struct {
-	name        string
-	description string
-	data        data.Repository
+	name                 string
+	description          string
+	data                 data.Repository
+	conflictPolicy       ConflictPolicy
+	environmentPrefix    string
+	configOption         string
+	configPath           string
+	responseFiles        bool
+	parent               *stateMachine
+	commands             []*stateMachine
+	selected             *stateMachine
+	topics               []model.Topic
+	isHelp               bool
+	helpTarget           *stateMachine
+	helpTopic            string
+	aliases              []string
+	commandAbbreviations bool
+	optionAbbreviations  bool
+	plugins              bool
+	pluginDirectories    []string
+	plugin               model.Plugin
+	pluginInput          []string
 }
 
 func (state *stateMachine) SetName(name string) {
@@ -45,6 +103,14 @@ func (state *stateMachine) GetName() string {
 	return state.name
 }
 
+func (state *stateMachine) GetPath() string {
+	var result = state.name
+	if state.parent != nil {
+		result = state.parent.GetPath() + " " + state.name
+	}
+	return result
+}
+
 func (state *stateMachine) SetDescription(description string) {
 	state.description = description
 }
@@ -53,35 +119,213 @@ func (state *stateMachine) GetDescription() string {
 	return state.description
 }
 
+func (state *stateMachine) AddCommand(name string, description string) (StateMachine, error) {
+	var result StateMachine = nil
+	var err error = nil
+	if !isValidCommandName(name) {
+		err = fmt.Errorf("unexpected command name: %s", name)
+	} else if state.findCommand(name) != nil || state.findTopic(name) != nil {
+		err = fmt.Errorf("command already defined: %s", name)
+	} else if len(state.data.GetArguments()) > 0 {
+		err = fmt.Errorf("commands can't be added next to arguments: %s", name)
+	} else {
+		command := &stateMachine{
+			name:           name,
+			description:    description,
+			data:           data.NewScopedRepository(state.data),
+			conflictPolicy: LastWins,
+			parent:         state,
+		}
+		state.commands = append(state.commands, command)
+		result = command
+	}
+	return result, err
+}
+
+func (state *stateMachine) AddAlias(alias string) error {
+	var result error = nil
+	if state.parent == nil {
+		result = fmt.Errorf("aliases can only be added to commands: %s", alias)
+	} else if !isValidCommandName(alias) {
+		result = fmt.Errorf("unexpected command alias: %s", alias)
+	} else if state.parent.findCommand(alias) != nil || state.parent.findTopic(alias) != nil {
+		result = fmt.Errorf("command already defined: %s", alias)
+	} else {
+		state.aliases = append(state.aliases, alias)
+	}
+	return result
+}
+
+func (state *stateMachine) GetAliases() []string {
+	return state.aliases
+}
+
+func (state *stateMachine) AddHelpCommand(name string, description string) error {
+	command, err := state.AddCommand(name, description)
+	if err == nil {
+		command.(*stateMachine).isHelp = true
+	}
+	return err
+}
+
+func (state *stateMachine) AddHelpTopic(name string, description string, text string) error {
+	var result error = nil
+	if !isValidCommandName(name) {
+		result = fmt.Errorf("unexpected help topic name: %s", name)
+	} else if state.findCommand(name) != nil || state.findTopic(name) != nil {
+		result = fmt.Errorf("help topic already defined: %s", name)
+	} else {
+		state.topics = append(state.topics, model.NewTopic(name, description, text))
+	}
+	return result
+}
+
+func (state *stateMachine) GetDefinedHelpTopics() []model.Topic {
+	return state.topics
+}
+
+func (state *stateMachine) GetHelpTopic(name string) model.Topic {
+	return state.findTopic(name)
+}
+
+func (state *stateMachine) GetDefinedCommands() []model.Command {
+	var result []model.Command
+	for _, command := range state.commands {
+		result = append(result, command)
+	}
+	return result
+}
+
+func (state *stateMachine) GetSelectedCommand() StateMachine {
+	var result StateMachine = nil
+	if state.selected != nil {
+		result = state.selected
+	}
+	return result
+}
+
+func (state *stateMachine) SetConflictPolicy(policy ConflictPolicy) {
+	state.getRoot().conflictPolicy = policy
+}
+
+func (state *stateMachine) SetEnvironmentPrefix(prefix string) {
+	root := state.getRoot()
+	root.environmentPrefix = prefix
+	root.applyEnvironmentPrefix(prefix)
+}
+
+func (state *stateMachine) applyEnvironmentPrefix(prefix string) {
+	for _, option := range state.data.GetOptions() {
+		option.SetEnvironmentPrefix(prefix)
+	}
+	for _, argument := range state.data.GetArguments() {
+		argument.SetEnvironmentPrefix(prefix)
+	}
+	for _, command := range state.commands {
+		command.applyEnvironmentPrefix(prefix)
+	}
+}
+
+func (state *stateMachine) SetConfigFile(optionName string, path string) error {
+	var result error = nil
+	option := state.data.GetOption(optionName)
+	if optionName != "" && option == nil {
+		result = fmt.Errorf("option not defined: %s", optionName)
+	} else if option != nil && (option.IsHelpTrigger() || option.IsCounter() || option.IsNegatable()) {
+		result = fmt.Errorf("option doesn't take a path: %s", optionName)
+	} else {
+		state.getRoot().configOption = optionName
+		state.getRoot().configPath = path
+	}
+	return result
+}
+
+func (state *stateMachine) SetResponseFilesEnabled(enabled bool) {
+	state.getRoot().responseFiles = enabled
+}
+
+func (state *stateMachine) SetCommandAbbreviationsEnabled(enabled bool) {
+	state.getRoot().commandAbbreviations = enabled
+}
+
+func (state *stateMachine) SetOptionAbbreviationsEnabled(enabled bool) {
+	state.getRoot().optionAbbreviations = enabled
+}
+
+func (state *stateMachine) SetPluginsEnabled(enabled bool) {
+	state.getRoot().plugins = enabled
+}
+
+func (state *stateMachine) SetPluginDirectories(directories []string) {
+	state.getRoot().pluginDirectories = directories
+}
+
+func (state *stateMachine) GetDefinedPlugins() []model.Plugin {
+	var result []model.Plugin
+	if state.acceptsPlugins() {
+		for _, plugin := range source.FindPlugins(state.getPluginPrefix(), state.getRoot().getPluginDirectories()) {
+			if isValidCommandName(plugin.Name) && state.findCommand(plugin.Name) == nil && state.findTopic(plugin.Name) == nil {
+				result = append(result, model.NewPlugin(plugin.Name, plugin.Path))
+			}
+		}
+	}
+	return result
+}
+
+func (state *stateMachine) GetSelectedPlugin() (model.Plugin, []string) {
+	var root = state.getRoot()
+	return root.plugin, root.pluginInput
+}
+
 func (state *stateMachine) DefineOption(shortName string, longName string, description string, pattern string) error {
 	var result error = nil
-	if shortName == "" && longName == "" {
-		result = fmt.Errorf("no name given for option")
-	} else if shortName != "" && !isValidOptionShortName(shortName) {
-		result = fmt.Errorf("unexpected short name: %s", shortName)
-	} else if longName != "" && !isValidOptionLongName(longName) {
-		result = fmt.Errorf("unexpected long name: %s", longName)
+	if err := validateOptionNames(shortName, longName, state.data); err != nil {
+		result = err
 	} else if !isValidRegularExpression(pattern) {
 		result = fmt.Errorf("unexpected option value pattern: %s", pattern)
-	} else if isOptionAlreadyDefined(shortName, longName, state.data) {
-		result = fmt.Errorf("option already defined: %s, %s", shortName, longName)
 	} else {
 		state.data.SaveOption(shortName, longName, description, pattern)
 	}
 	return result
 }
 
-func (state *stateMachine) DefineHelpOption(shortName string, longName string, description string) error {
+func (state *stateMachine) DefineRepeatableOption(shortName string, longName string, description string, minCount int, maxCount int, pattern string) error {
 	var result error = nil
-	if shortName == "" && longName == "" {
-		result = fmt.Errorf("no name given for option")
-	} else if shortName != "" && !isValidOptionShortName(shortName) {
-		result = fmt.Errorf("unexpected short name: %s", shortName)
-	} else if longName != "" && !isValidOptionLongName(longName) {
-		result = fmt.Errorf("unexpected long name: %s", longName)
-	} else if isOptionAlreadyDefined(shortName, longName, state.data) {
-		result = fmt.Errorf("option already defined: %s, %s", shortName, longName)
+	if err := validateOptionNames(shortName, longName, state.data); err != nil {
+		result = err
+	} else if !isValidOptionCountRange(minCount, maxCount) {
+		result = fmt.Errorf("unexpected range: [%d..%d]", minCount, maxCount)
+	} else if !isValidRegularExpression(pattern) {
+		result = fmt.Errorf("unexpected option value pattern: %s", pattern)
 	} else {
+		state.data.SaveRepeatableOption(shortName, longName, description, minCount, maxCount, pattern)
+	}
+	return result
+}
+
+func (state *stateMachine) DefineCounterOption(shortName string, longName string, description string) error {
+	var result error = validateOptionNames(shortName, longName, state.data)
+	if result == nil {
+		state.data.SaveCounterOption(shortName, longName, description)
+	}
+	return result
+}
+
+func (state *stateMachine) DefineBooleanOption(shortName string, longName string, description string) error {
+	var result error = nil
+	if err := validateOptionNames(shortName, longName, state.data); err != nil {
+		result = err
+	} else if longName != "" && state.data.GetOption(configuration.NegatedOptionPrefix+longName) != nil {
+		result = fmt.Errorf("option already defined: %s%s", configuration.NegatedOptionPrefix, longName)
+	} else {
+		state.data.SaveBooleanOption(shortName, longName, description)
+	}
+	return result
+}
+
+func (state *stateMachine) DefineHelpOption(shortName string, longName string, description string) error {
+	var result error = validateOptionNames(shortName, longName, state.data)
+	if result == nil {
 		state.data.SaveHelpOption(shortName, longName, description)
 	}
 	return result
@@ -96,13 +340,87 @@ func (state *stateMachine) GetOptionValue(name string) string {
 	value := state.data.GetOptionValue(name)
 	if value == "" && option != nil && option.IsParsed() {
 		value = "true"
+	} else if value == "" && option != nil && option.HasDefaultValue() {
+		value = option.GetDefaultValue()
 	}
 	return value
 }
 
+func (state *stateMachine) GetOptionValues(name string) []string {
+	option := state.data.GetOption(name)
+	values := state.data.GetOptionValues(name)
+	if len(values) == 0 && option != nil && !option.IsParsed() && option.HasDefaultValue() {
+		values = []string{option.GetDefaultValue()}
+	}
+	return values
+}
+
+func (state *stateMachine) GetOptionCount(name string) int {
+	var result = 0
+	if option := state.data.GetOption(name); option != nil {
+		result = option.GetParsedCount()
+	}
+	return result
+}
+
+func (state *stateMachine) GetOptionSources(name string) []model.Source {
+	option := state.data.GetOption(name)
+	sources := state.data.GetOptionSources(name)
+	if len(sources) == 0 && option != nil && option.HasDefaultValue() {
+		sources = []model.Source{model.NewDefaultSource()}
+	}
+	return sources
+}
+
+func (state *stateMachine) SetOptionDefaultValue(name string, value string) error {
+	var result error = nil
+	option := state.data.GetOption(name)
+	if option == nil {
+		result = fmt.Errorf("option not defined: %s", name)
+	} else if option.IsHelpTrigger() || option.IsCounter() {
+		result = fmt.Errorf("option doesn't take a value: %s", name)
+	} else if !isValidValue(option.GetPattern(), value) {
+		result = fmt.Errorf("unexpected default value for %s: %s", name, value)
+	} else {
+		option.SetDefaultValue(value)
+	}
+	return result
+}
+
+func (state *stateMachine) SetOptionRequired(name string) error {
+	var result error = nil
+	option := state.data.GetOption(name)
+	if option == nil {
+		result = fmt.Errorf("option not defined: %s", name)
+	} else if option.IsHelpTrigger() {
+		result = fmt.Errorf("help option can't be required: %s", name)
+	} else {
+		option.SetRequired()
+	}
+	return result
+}
+
+func (state *stateMachine) SetOptionEnvironmentVariable(name string, variable string) error {
+	var result error = nil
+	option := state.data.GetOption(name)
+	if option == nil {
+		result = fmt.Errorf("option not defined: %s", name)
+	} else if option.IsHelpTrigger() || option.IsCounter() {
+		result = fmt.Errorf("option doesn't take a value: %s", name)
+	} else if !isValidEnvironmentVariable(variable) {
+		result = fmt.Errorf("unexpected environment variable name: %s", variable)
+	} else {
+		option.SetEnvironmentVariable(variable)
+		option.SetEnvironmentPrefix(state.getRoot().environmentPrefix)
+	}
+	return result
+}
+
 func (state *stateMachine) DefineArgument(name string, description string, minCount int, maxCount int, pattern string) error {
 	var result error = nil
-	if !isValidArgumentCountRange(minCount, maxCount) {
+	if len(state.commands) > 0 {
+		result = fmt.Errorf("arguments can't be defined next to commands: %s", name)
+	} else if !isValidArgumentCountRange(minCount, maxCount) {
 		result = fmt.Errorf("unexpected range: [%d..%d]", minCount, maxCount)
 	} else if !isValidArgumentName(name) {
 		result = fmt.Errorf("unexpected argument name: %s", name)
@@ -122,53 +440,611 @@ func (state *stateMachine) GetDefinedArguments() []model.Argument {
 }
 
 func (state *stateMachine) GetArgumentValues(name string) []string {
-	return state.data.GetArgumentValues(name)
+	argument := state.data.GetArgument(name)
+	values := state.data.GetArgumentValues(name)
+	if len(values) == 0 && argument != nil && argument.HasDefaultValues() {
+		values = argument.GetDefaultValues()
+	}
+	return values
 }
 
-func (state *stateMachine) Parse() error {
+func (state *stateMachine) GetArgumentSources(name string) []model.Source {
+	argument := state.data.GetArgument(name)
+	sources := state.data.GetArgumentSources(name)
+	if len(sources) == 0 && argument != nil && argument.HasDefaultValues() {
+		for range argument.GetDefaultValues() {
+			sources = append(sources, model.NewDefaultSource())
+		}
+	}
+	return sources
+}
+
+func (state *stateMachine) SetArgumentDefaultValues(name string, values []string) error {
 	var result error = nil
+	argument := state.data.GetArgument(name)
+	if argument == nil {
+		result = fmt.Errorf("argument not defined: %s", name)
+	} else if len(values) < argument.GetMinValuesCount() || len(values) > argument.GetMaxValuesCount() {
+		result = fmt.Errorf("unexpected number of default values for %s: %d", name, len(values))
+	} else if value, isValid := findInvalidValue(argument.GetPattern(), values); !isValid {
+		result = fmt.Errorf("unexpected default value for %s: %s", name, value)
+	} else {
+		argument.SetDefaultValues(values)
+	}
+	return result
+}
+
+func (state *stateMachine) SetArgumentEnvironmentVariable(name string, variable string) error {
+	var result error = nil
+	argument := state.data.GetArgument(name)
+	if argument == nil {
+		result = fmt.Errorf("argument not defined: %s", name)
+	} else if !isValidEnvironmentVariable(variable) {
+		result = fmt.Errorf("unexpected environment variable name: %s", variable)
+	} else {
+		argument.SetEnvironmentVariable(variable)
+		argument.SetEnvironmentPrefix(state.getRoot().environmentPrefix)
+	}
+	return result
+}
+
+func (state *stateMachine) Parse() error {
+	return state.ParseArgs(os.Args[1:])
+}
+
+func (state *stateMachine) ParseArgs(input []string) error {
+	return state.getRoot().parseInput(input)
+}
+
+func (state *stateMachine) parseInput(input []string) error {
 	var currentOptionName string = ""
+	var isEndOfOptions bool = false
+	var active = state
+	var positionals []positionalValue
 
-	state.data.ClearValues()
+	state.clearValues()
+	tokens, result := state.readInputTokens(input)
 
-	for _, data := range getInput() {
-		if isExpectedOption(data, state.data) {
-			currentOptionName = strings.Trim(data, "-")
-			option := state.data.GetOption(currentOptionName)
-			option.SetParsed()
-			if option.IsHelpTrigger() {
-				result = fmt.Errorf("")
-				break
-			}
-		} else if isExpectedOptionValue(currentOptionName, data, state.data) {
-			state.data.SaveOptionValue(currentOptionName, data)
+	for position, token := range tokens {
+		data := token.Value
+		index := token.Index
+		origin := getTokenSource(token)
+		if data == configuration.EndOfOptionsToken && !isEndOfOptions {
+			isEndOfOptions = true
+			currentOptionName = ""
+		} else if !isEndOfOptions && isOptionToken(data, active.data) {
+			currentOptionName, result = active.parseOptionToken(data, index, origin)
+		} else if isExpectedOptionValue(currentOptionName, data, active.data) && !active.isCommandToken(data, isEndOfOptions) {
+			active.data.SaveOptionValue(currentOptionName, data)
 			currentOptionName = ""
-		} else if argument := findArgumentForValue(data, state.data); argument != nil {
-			state.data.SaveArgumentValue(argument.GetName(), data)
+		} else if active.isHelp && !isEndOfOptions {
+			result = active.parseHelpToken(data, index)
+			currentOptionName = ""
+		} else if commands := active.matchCommands(data); len(commands) > 1 && !isEndOfOptions {
+			result = newAmbiguousCommandError(data, index, commands)
+		} else if command := active.matchCommand(data); command != nil && command.isHelp && !isEndOfOptions {
+			command.helpTarget = active
+			command.helpTopic = ""
+			active = command
+			currentOptionName = ""
+		} else if command := active.matchCommand(data); command != nil && !isEndOfOptions {
+			active.selected = command
+			active = command
+			currentOptionName = ""
+		} else if plugin := active.findPlugin(data); plugin != nil && !isEndOfOptions {
+			state.plugin = plugin
+			state.pluginInput = getTokenValues(tokens[position+1:])
+		} else if len(active.commands) > 0 && !isEndOfOptions {
+			result = &ParseError{Kind: UnknownCommand, Token: data, Index: index}
+		} else if argument := findArgumentForValue(data, active.data); argument != nil {
+			positionals = append(positionals, positionalValue{token: token, origin: origin})
 			currentOptionName = ""
 		} else {
-			result = fmt.Errorf("unexpected input: %s", data)
+			result = &ParseError{Kind: UnmatchedValue, Token: data, Index: index, Definition: currentOptionName}
+		}
+
+		if result != nil {
+			result.Source = getTokenLocation(token)
+			break
+		} else if state.plugin != nil {
 			break
 		}
 	}
 
+	if result == nil && state.plugin == nil {
+		result = active.assignArgumentValues(positionals)
+	}
+
+	if result == nil && active.isHelp {
+		result = &ParseError{Kind: HelpRequested, Token: active.helpTopic, Index: -1, Definition: active.name}
+	}
+
 	if result == nil {
-		missing := getUnsatisfiedArguments(state.data)
+		result = active.parseOptionEnvironment()
+	}
+
+	if result == nil {
+		result = active.parseArgumentEnvironment()
+	}
+
+	if result == nil {
+		result = active.parseConfigFile()
+	}
+
+	if result == nil && len(active.commands) > 0 && state.plugin == nil {
+		names := strings.Join(active.getCommandNames(), ", ")
+		result = &ParseError{Kind: MissingCommand, Index: -1, Definition: names}
+	}
+
+	if result == nil && state.plugin == nil {
+		missing := append(getUnsatisfiedArguments(active.data), getUnsatisfiedOptions(active.data)...)
 		if len(missing) > 0 {
 			names := strings.Join(missing, ", ")
-			result = fmt.Errorf("missing input for: %s", names)
+			result = &ParseError{Kind: MissingArgument, Index: -1, Definition: names}
+		}
+	}
+
+	if result != nil {
+		result.Suggestion = active.findSuggestion(result)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return result
+}
+
+func (state *stateMachine) parseHelpToken(input string, index int) *ParseError {
+	var result *ParseError = nil
+	var target = state.helpTarget
+	if commands := target.matchCommands(input); len(commands) > 1 && state.helpTopic == "" {
+		result = newAmbiguousCommandError(input, index, commands)
+	} else if command := target.matchCommand(input); command != nil && (!command.isHelp || command == state) && state.helpTopic == "" {
+		target.selected = command
+		state.helpTarget = command
+	} else if topic := target.findTopic(input); topic != nil && state.helpTopic == "" {
+		state.helpTopic = input
+	} else {
+		result = &ParseError{Kind: UnknownCommand, Token: input, Index: index}
+	}
+	return result
+}
+
+func (state *stateMachine) clearValues() {
+	state.data.ClearValues()
+	state.selected = nil
+	state.plugin = nil
+	state.pluginInput = nil
+	for _, command := range state.commands {
+		command.clearValues()
+	}
+}
+
+func (state *stateMachine) getRoot() *stateMachine {
+	var result = state
+	for result.parent != nil {
+		result = result.parent
+	}
+	return result
+}
+
+func (state *stateMachine) findCommand(name string) *stateMachine {
+	var result *stateMachine = nil
+	for _, command := range state.commands {
+		if command.name == name || containsString(command.aliases, name) {
+			result = command
+			break
+		}
+	}
+	return result
+}
+
+// isCommandToken tells whether the input selects, or ambiguously abbreviates,
+// a command. Such input is never taken as the value of a preceding option.
+func (state *stateMachine) isCommandToken(input string, isEndOfOptions bool) bool {
+	return !isEndOfOptions && (state.matchCommand(input) != nil || len(state.matchCommands(input)) > 1)
+}
+
+// matchCommand finds the command with the given name or alias. If there is
+// none, and abbreviations are enabled, the only command with a name or alias
+// starting with the given name is returned instead.
+func (state *stateMachine) matchCommand(name string) *stateMachine {
+	var result = state.findCommand(name)
+	if commands := state.matchCommands(name); result == nil && len(commands) == 1 {
+		result = commands[0]
+	}
+	return result
+}
+
+// matchCommands returns the commands with a name or alias starting with the
+// given name, if abbreviations are enabled and the name isn't an exact match.
+func (state *stateMachine) matchCommands(name string) []*stateMachine {
+	var result []*stateMachine
+	if state.getRoot().commandAbbreviations && name != "" && state.findCommand(name) == nil {
+		for _, command := range state.commands {
+			if hasPrefixedName(append([]string{command.name}, command.aliases...), name) {
+				result = append(result, command)
+			}
+		}
+	}
+	return result
+}
+
+func (state *stateMachine) findTopic(name string) model.Topic {
+	var result model.Topic = nil
+	for _, topic := range state.topics {
+		if topic.GetName() == name {
+			result = topic
+			break
 		}
 	}
+	return result
+}
 
+// findPlugin looks up an external executable named after the command path
+// and the given name, e.g. "tool-foo" for `tool foo`, when plugins are
+// enabled and the name is expected to be a command.
+func (state *stateMachine) findPlugin(name string) model.Plugin {
+	var result model.Plugin = nil
+	if state.acceptsPlugins() && isValidCommandName(name) {
+		if path, isFound := source.FindPlugin(state.getPluginPrefix(), name, state.getRoot().getPluginDirectories()); isFound {
+			result = model.NewPlugin(name, path)
+		}
+	}
+	return result
+}
+
+func (state *stateMachine) acceptsPlugins() bool {
+	return state.getRoot().plugins && !state.isHelp && len(state.data.GetArguments()) == 0
+}
+
+func (state *stateMachine) getPluginPrefix() string {
+	return strings.Replace(state.GetPath(), " ", "-", -1) + "-"
+}
+
+func (state *stateMachine) getPluginDirectories() []string {
+	var result = state.pluginDirectories
+	if result == nil {
+		result = source.GetPathDirectories()
+	}
 	return result
 }
 
+func (state *stateMachine) getCommandNames() []string {
+	var result []string
+	for _, command := range state.commands {
+		result = append(result, command.name)
+	}
+	return result
+}
+
+func (state *stateMachine) readInputTokens(input []string) ([]source.Token, *ParseError) {
+	var result *ParseError = nil
+	var tokens = source.NewTokens(input)
+	if state.responseFiles {
+		var err error
+		if tokens, err = source.ExpandResponseFiles(input, configuration.EndOfOptionsToken); err != nil {
+			tokens = nil
+			result = newResponseFileError(err)
+		}
+	}
+	return tokens, result
+}
+
+func (state *stateMachine) parseOptionToken(input string, index int, origin model.Source) (string, *ParseError) {
+	var pendingOptionName = ""
+	expanded, result := state.expandOptionPrefix(input, index)
+
+	var tokens []optionToken
+	if result == nil {
+		tokens = splitOptionToken(expanded, state.data)
+	}
+
+	for _, token := range tokens {
+		option := state.data.GetOption(token.name)
+		if option == nil {
+			result = &ParseError{Kind: UnknownOption, Token: input, Index: index}
+		} else if option.GetParsedCount() >= option.GetMaxValuesCount() {
+			result = &ParseError{Kind: TooManyValues, Token: input, Index: index, Definition: getOptionName(option)}
+		} else {
+			option.SetParsed()
+			state.data.SaveOptionSource(token.name, origin)
+			if option.IsHelpTrigger() {
+				result = &ParseError{Kind: HelpRequested, Token: input, Index: index, Definition: getOptionName(option)}
+			} else if option.IsNegatable() {
+				result = state.parseBooleanOptionToken(option, token, input, index)
+			} else if !token.hasValue {
+				if !option.IsCounter() {
+					pendingOptionName = token.name
+				}
+			} else if !option.IsCounter() && isValidValue(option.GetPattern(), token.value) {
+				state.data.SaveOptionValue(token.name, token.value)
+			} else {
+				result = &ParseError{Kind: UnmatchedValue, Token: input, Index: index, Definition: getOptionName(option)}
+			}
+		}
+
+		if result != nil {
+			pendingOptionName = ""
+			break
+		}
+	}
+
+	return pendingOptionName, result
+}
+
+// expandOptionPrefix replaces an abbreviated long option name with the full
+// name, e.g. `--verb=2` with `--verbose=2`, if option abbreviations are
+// enabled and the abbreviation is unique. Exact names always win.
+func (state *stateMachine) expandOptionPrefix(input string, index int) (string, *ParseError) {
+	var result = input
+	var err *ParseError = nil
+	var name, suffix = input, ""
+	if separator := strings.Index(input, "="); separator > 2 {
+		name, suffix = input[:separator], input[separator:]
+	}
+
+	if state.getRoot().optionAbbreviations && strings.HasPrefix(name, "--") && len(name) > 2 && !isKnownLongOptionName(name[2:], state.data) {
+		candidates := findPrefixedLongOptionNames(name[2:], state.data)
+		if len(candidates) == 1 {
+			result = "--" + candidates[0] + suffix
+		} else if len(candidates) > 1 {
+			definition := "--" + strings.Join(candidates, ", --")
+			err = &ParseError{Kind: AmbiguousOption, Token: name, Index: index, Definition: definition}
+		}
+	}
+	return result, err
+}
+
+func (state *stateMachine) parseBooleanOptionToken(option model.Option, token optionToken, input string, index int) *ParseError {
+	var result *ParseError = nil
+	var value = "true"
+	if token.hasValue {
+		value = token.value
+	}
+
+	if !isValidValue(option.GetPattern(), value) {
+		result = &ParseError{Kind: UnmatchedValue, Token: input, Index: index, Definition: getOptionName(option)}
+	} else if state.getRoot().conflictPolicy == RejectConflicts && hasConflictingValue(value, state.data.GetOptionValues(token.name)) {
+		result = &ParseError{Kind: ConflictingValues, Token: input, Index: index, Definition: getOptionName(option)}
+	} else {
+		state.data.SaveOptionValue(token.name, value)
+	}
+
+	return result
+}
+
+func (state *stateMachine) parseOptionEnvironment() *ParseError {
+	var result *ParseError = nil
+	for _, option := range state.data.GetOptions() {
+		name := getOptionName(option)
+		isGiven := option.IsParsed() || len(state.data.GetOptionValues(name)) > 0
+		if value, isSet := lookupEnvironment(option); isSet && !isGiven {
+			if isValidValue(option.GetPattern(), value) {
+				state.saveOptionValue(name, value, model.NewEnvironmentSource(option.GetEnvironmentVariable()))
+			} else {
+				result = newEnvironmentValueError(value, name, option)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func (state *stateMachine) parseArgumentEnvironment() *ParseError {
+	var result *ParseError = nil
+	for _, argument := range state.data.GetArguments() {
+		name := argument.GetName()
+		isGiven := len(state.data.GetArgumentValues(name)) > 0
+		if value, isSet := lookupEnvironment(argument); isSet && !isGiven {
+			if isValidValue(argument.GetPattern(), value) {
+				state.saveArgumentValue(name, value, model.NewEnvironmentSource(argument.GetEnvironmentVariable()))
+			} else {
+				result = newEnvironmentValueError(value, name, argument)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func (state *stateMachine) parseConfigFile() *ParseError {
+	var result *ParseError = nil
+	path, isExplicit := state.getConfigFilePath()
+	if path != "" {
+		entries, err := source.ReadFile(path)
+		if err != nil && (isExplicit || !os.IsNotExist(err)) {
+			result = newConfigFileError(path, err)
+		} else if err == nil {
+			result = state.parseConfigEntries(path, entries)
+		}
+	}
+	return result
+}
+
+func (state *stateMachine) getConfigFilePath() (string, bool) {
+	var root = state.getRoot()
+	var path = root.configPath
+	var isExplicit = false
+	if root.configOption != "" {
+		if values := state.data.GetOptionValues(root.configOption); len(values) > 0 {
+			path = values[len(values)-1]
+			isExplicit = true
+		}
+	}
+	return path, isExplicit
+}
+
+func (state *stateMachine) parseConfigEntries(path string, entries []source.Entry) *ParseError {
+	var result *ParseError = nil
+	var root = state.getRoot()
+	for _, entry := range entries {
+		fileSource := model.NewConfigFileSource(path, entry.Line)
+		owner, key := root.findConfigKeyOwner(entry.Key)
+		if option := findConfigOption(entry.Key, state.data); option != nil {
+			result = state.parseOptionConfigEntry(option, entry, fileSource)
+		} else if argument := state.data.GetArgument(entry.Key); argument != nil {
+			result = state.parseArgumentConfigEntry(argument, entry, fileSource)
+		} else if option := findConfigOption(key, state.data); option != nil && owner.isSelfOrAncestorOf(state) {
+			result = state.parseOptionConfigEntry(option, entry, fileSource)
+		} else if argument := state.data.GetArgument(key); argument != nil && owner == state {
+			result = state.parseArgumentConfigEntry(argument, entry, fileSource)
+		} else if !root.definesName(entry.Key) && !owner.definesName(key) {
+			result = &ParseError{Kind: UnknownOption, Token: entry.Key, Index: -1, Source: getConfigFileLocation(fileSource)}
+		}
+
+		if result != nil {
+			break
+		}
+	}
+	return result
+}
+
+// findConfigKeyOwner maps the leading dotted parts of a config key that name
+// commands to those commands, e.g. "deploy.target" to the "target" key of the
+// "deploy" command, as given in a `[deploy]` INI section or TOML table.
+func (state *stateMachine) findConfigKeyOwner(key string) (*stateMachine, string) {
+	var owner = state
+	var rest = key
+	for index := strings.Index(rest, "."); index > 0 && owner.findCommand(rest[:index]) != nil; index = strings.Index(rest, ".") {
+		owner = owner.findCommand(rest[:index])
+		rest = rest[index+1:]
+	}
+	return owner, rest
+}
+
+func (state *stateMachine) isSelfOrAncestorOf(other *stateMachine) bool {
+	var result = false
+	for current := other; !result && current != nil; current = current.parent {
+		result = current == state
+	}
+	return result
+}
+
+// definesName tells whether an option or argument with the given name is
+// defined on the state or any of its commands. Config file keys defined for
+// a command other than the selected one are ignored, allowing one config
+// file to serve the whole command tree.
+func (state *stateMachine) definesName(name string) bool {
+	var result = findConfigOption(name, state.data) != nil || state.data.GetArgument(name) != nil
+	for index := 0; !result && index < len(state.commands); index++ {
+		result = state.commands[index].definesName(name)
+	}
+	return result
+}
+
+// findConfigOption returns the option the config key names. Options are
+// keyed by their long name, so a short name key like "o" doesn't silently
+// set "--output". Options without a long name are keyed by their short name.
+func findConfigOption(key string, data data.Repository) model.Option {
+	var result = data.GetOption(key)
+	if result != nil && getOptionName(result) != key {
+		result = nil
+	}
+	return result
+}
+
+func (state *stateMachine) parseOptionConfigEntry(option model.Option, entry source.Entry, fileSource model.Source) *ParseError {
+	var result *ParseError = nil
+	location := getConfigFileLocation(fileSource)
+	name := getOptionName(option)
+	isGiven := option.IsParsed() || len(state.data.GetOptionValues(name)) > 0
+	if option.IsHelpTrigger() || option.IsCounter() {
+		result = &ParseError{Kind: InvalidValue, Token: strings.Join(entry.Values, ", "), Index: -1, Definition: name, Source: location}
+	} else if len(entry.Values) > option.GetMaxValuesCount() {
+		result = &ParseError{Kind: TooManyValues, Token: strings.Join(entry.Values, ", "), Index: -1, Definition: name, Source: location}
+	} else if value, isValid := findInvalidValue(option.GetPattern(), entry.Values); !isValid {
+		result = &ParseError{Kind: InvalidValue, Token: value, Index: -1, Definition: name, Source: location}
+	} else if !isGiven {
+		for _, value := range entry.Values {
+			state.saveOptionValue(name, value, fileSource)
+		}
+	}
+	return result
+}
+
+func (state *stateMachine) parseArgumentConfigEntry(argument model.Argument, entry source.Entry, fileSource model.Source) *ParseError {
+	var result *ParseError = nil
+	location := getConfigFileLocation(fileSource)
+	name := argument.GetName()
+	isGiven := len(state.data.GetArgumentValues(name)) > 0
+	if len(entry.Values) > argument.GetMaxValuesCount() {
+		result = &ParseError{Kind: TooManyValues, Token: strings.Join(entry.Values, ", "), Index: -1, Definition: name, Source: location}
+	} else if value, isValid := findInvalidValue(argument.GetPattern(), entry.Values); !isValid {
+		result = &ParseError{Kind: InvalidValue, Token: value, Index: -1, Definition: name, Source: location}
+	} else if !isGiven {
+		for _, value := range entry.Values {
+			state.saveArgumentValue(name, value, fileSource)
+		}
+	}
+	return result
+}
+
+func (state *stateMachine) saveOptionValue(name string, value string, valueSource model.Source) {
+	state.data.SaveOptionValue(name, value)
+	state.data.SaveOptionSource(name, valueSource)
+}
+
+func (state *stateMachine) saveArgumentValue(name string, value string, valueSource model.Source) {
+	state.data.SaveArgumentValue(name, value)
+	state.data.SaveArgumentSource(name, valueSource)
+}
+
 func (state *stateMachine) Reset() {
 	state.data.ClearAll()
+	state.commands = nil
+	state.selected = nil
+	state.topics = nil
+}
+
+func isKnownLongOptionName(name string, data data.Repository) bool {
+	return data.GetOption(name) != nil || findNegatedOption(name, data) != nil
+}
+
+// findPrefixedLongOptionNames returns the long option names, including the
+// negated names of boolean options, that start with the given prefix.
+func findPrefixedLongOptionNames(prefix string, data data.Repository) []string {
+	var result []string
+	for _, option := range data.GetOptions() {
+		name := option.GetLongName()
+		if name != "" && strings.HasPrefix(name, prefix) {
+			result = append(result, name)
+		}
+		if name != "" && option.IsNegatable() && strings.HasPrefix(configuration.NegatedOptionPrefix+name, prefix) {
+			result = append(result, configuration.NegatedOptionPrefix+name)
+		}
+	}
+	return result
+}
+
+func hasPrefixedName(names []string, prefix string) bool {
+	var result = false
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			result = true
+			break
+		}
+	}
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	var result = false
+	for _, item := range values {
+		if item == value {
+			result = true
+			break
+		}
+	}
+	return result
 }
 
-func getInput() []string {
-	return os.Args[1:]
+func newAmbiguousCommandError(input string, index int, commands []*stateMachine) *ParseError {
+	var names []string
+	for _, command := range commands {
+		names = append(names, command.name)
+	}
+	return &ParseError{Kind: AmbiguousCommand, Token: input, Index: index, Definition: strings.Join(names, ", ")}
 }
 
 func isValidOptionShortName(name string) bool {
@@ -179,43 +1055,164 @@ func isValidOptionLongName(name string) bool {
 	return configuration.OptionLongNamePattern.MatchString(name)
 }
 
+func validateOptionNames(shortName string, longName string, data data.Repository) error {
+	var result error = nil
+	if shortName == "" && longName == "" {
+		result = fmt.Errorf("no name given for option")
+	} else if shortName != "" && !isValidOptionShortName(shortName) {
+		result = fmt.Errorf("unexpected short name: %s", shortName)
+	} else if longName != "" && !isValidOptionLongName(longName) {
+		result = fmt.Errorf("unexpected long name: %s", longName)
+	} else if isOptionAlreadyDefined(shortName, longName, data) {
+		result = fmt.Errorf("option already defined: %s, %s", shortName, longName)
+	} else if findNegatedOption(longName, data) != nil {
+		result = fmt.Errorf("option already defined: %s", longName)
+	}
+	return result
+}
+
+func isValidOptionCountRange(min int, max int) bool {
+	return min >= 0 && max >= 1 && min <= max
+}
+
 func isOptionAlreadyDefined(shortName string, longName string, data data.Repository) bool {
 	return data.GetOption(shortName) != nil || data.GetOption(longName) != nil
 }
 
-func isExpectedOption(input string, data data.Repository) bool {
+func isOptionToken(input string, data data.Repository) bool {
 	var result = false
-	if strings.HasPrefix(input, "-") {
-		name := strings.Trim(input, "-")
-		if option := data.GetOption(name); option != nil {
-			if value := data.GetOptionValue(name); value == "" {
-				result = true
+	if len(input) > 1 && strings.HasPrefix(input, "-") {
+		result = !isNegativeNumber(input) || data.GetOption(strings.Trim(input, "-")) != nil
+	}
+	return result
+}
+
+func isNegativeNumber(input string) bool {
+	return configuration.NegativeNumberPattern.MatchString(input)
+}
+
+func getOptionDisplayName(option model.Option) string {
+	var result = "--" + option.GetLongName()
+	if option.GetLongName() == "" {
+		result = "-" + option.GetShortName()
+	}
+	return result
+}
+
+func getOptionName(option model.Option) string {
+	var result = option.GetLongName()
+	if result == "" {
+		result = option.GetShortName()
+	}
+	return result
+}
+
+type optionToken struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+// splitOptionToken breaks an option input token into the options it names.
+// Long options may carry an attached value (`--name=value`). Short options
+// may be clustered (`-abc`), where the first character that is followed by
+// anything but defined short options takes the rest as its value (`-ovalue`).
+func splitOptionToken(input string, data data.Repository) []optionToken {
+	var result []optionToken
+	var name = strings.Trim(input, "-")
+	if strings.HasPrefix(input, "--") {
+		if index := strings.Index(input, "="); index > 2 {
+			result = append(result, optionToken{name: input[2:index], value: input[index+1:], hasValue: true})
+		} else if negated := findNegatedOption(name, data); negated != nil {
+			result = append(result, optionToken{name: negated.GetLongName(), value: "false", hasValue: true})
+		} else {
+			result = append(result, optionToken{name: name})
+		}
+	} else if len(name) <= 1 || data.GetOption(name) != nil {
+		result = append(result, optionToken{name: name})
+	} else {
+		for index := range name {
+			shortName := name[index : index+1]
+			rest := name[index+1:]
+			option := data.GetOption(shortName)
+			if rest == "" || option == nil || isFlagOption(option) || areDefinedShortNames(rest, data) {
+				result = append(result, optionToken{name: shortName})
+			} else {
+				result = append(result, optionToken{name: shortName, value: rest, hasValue: true})
+				break
 			}
 		}
 	}
 	return result
 }
 
+func findNegatedOption(name string, data data.Repository) model.Option {
+	var result model.Option = nil
+	if strings.HasPrefix(name, configuration.NegatedOptionPrefix) && data.GetOption(name) == nil {
+		option := data.GetOption(strings.TrimPrefix(name, configuration.NegatedOptionPrefix))
+		if option != nil && option.IsNegatable() && option.GetLongName() != "" {
+			result = option
+		}
+	}
+	return result
+}
+
+func hasConflictingValue(value string, values []string) bool {
+	var result = false
+	for _, existing := range values {
+		if existing != value {
+			result = true
+			break
+		}
+	}
+	return result
+}
+
+// isFlagOption tells whether the option never takes a detached or clustered
+// value, in which case any following characters of a short option cluster
+// are more options. Boolean options only take attached values in their long
+// form, e.g. `--color=false`.
+func isFlagOption(option model.Option) bool {
+	return option.IsCounter() || option.IsNegatable()
+}
+
+func areDefinedShortNames(names string, data data.Repository) bool {
+	var result = true
+	for index := range names {
+		if data.GetOption(names[index:index+1]) == nil {
+			result = false
+			break
+		}
+	}
+	return result
+}
+
 func isExpectedOptionValue(name string, input string, data data.Repository) bool {
 	var result = false
-	if option := data.GetOption(name); option != nil {
-		if option.IsParsed() {
-			if value := data.GetOptionValue(name); value == "" {
-				if test, err := regexp.Compile(option.GetPattern()); err == nil {
-					if test.MatchString(value) {
-						result = true
-					}
-				}
-			}
+	if name != "" {
+		if option := data.GetOption(name); option != nil {
+			result = isValidValue(option.GetPattern(), input)
 		}
 	}
 	return result
 }
 
+func isValidValue(pattern string, value string) bool {
+	var result = false
+	if test, err := regexp.Compile(pattern); err == nil {
+		result = test.MatchString(value)
+	}
+	return result
+}
+
 func isValidArgumentCountRange(min int, max int) bool {
 	return min >= 1 && min <= max
 }
 
+func isValidCommandName(name string) bool {
+	return configuration.CommandNamePattern.MatchString(name)
+}
+
 func isValidArgumentName(name string) bool {
 	return configuration.ArgumentNamePattern.MatchString(name)
 }
@@ -228,14 +1225,9 @@ func findArgumentForValue(value string, data data.Repository) model.Argument {
 	var result model.Argument = nil
 	var arguments = data.GetArguments()
 	for _, argument := range arguments {
-		values := data.GetArgumentValues(argument.GetName())
-		if len(values) < argument.GetMaxValuesCount() {
-			if test, err := regexp.Compile(argument.GetPattern()); err == nil {
-				if test.MatchString(value) {
-					result = argument
-					break
-				}
-			}
+		if isValidValue(argument.GetPattern(), value) {
+			result = argument
+			break
 		}
 	}
 	return result
@@ -247,13 +1239,132 @@ func getUnsatisfiedArguments(data data.Repository) []string {
 	for _, argument := range arguments {
 		name := argument.GetName()
 		values := data.GetArgumentValues(name)
-		if len(values) < argument.GetMinValuesCount() {
+		usesDefaults := len(values) == 0 && argument.HasDefaultValues()
+		if len(values) < argument.GetMinValuesCount() && !usesDefaults {
 			missing = append(missing, name)
 		}
 	}
 	return missing
 }
 
+func getUnsatisfiedOptions(data data.Repository) []string {
+	var missing []string
+	var options = data.GetOptions()
+	for _, option := range options {
+		if getOptionValueCount(option, data) < option.GetMinValuesCount() {
+			missing = append(missing, getOptionDisplayName(option))
+		} else if option.IsRequired() && !isOptionSatisfied(option, data) {
+			missing = append(missing, getOptionDisplayName(option))
+		}
+	}
+	return missing
+}
+
+func findInvalidValue(pattern string, values []string) (string, bool) {
+	var invalid = ""
+	var isValid = true
+	for _, value := range values {
+		if !isValidValue(pattern, value) {
+			invalid = value
+			isValid = false
+			break
+		}
+	}
+	return invalid, isValid
+}
+
+// getOptionValueCount returns the number of times the option was given on
+// the command line or, if it wasn't, the number of values it got from the
+// environment, a config file or its declared default value.
+func getOptionValueCount(option model.Option, data data.Repository) int {
+	var result = option.GetParsedCount()
+	if result == 0 {
+		result = len(data.GetOptionValues(getOptionName(option)))
+	}
+	if result == 0 && option.HasDefaultValue() {
+		result = 1
+	}
+	return result
+}
+
+func isOptionSatisfied(option model.Option, data data.Repository) bool {
+	var result = option.IsParsed() || option.HasDefaultValue()
+	if !result {
+		result = len(data.GetOptionValues(getOptionName(option))) > 0
+	}
+	return result
+}
+
+func lookupEnvironment(definition model.Bindable) (string, bool) {
+	var value = ""
+	var isSet = false
+	if variable := definition.GetEnvironmentVariable(); variable != "" {
+		value, isSet = os.LookupEnv(variable)
+	}
+	return value, isSet
+}
+
+func newEnvironmentValueError(value string, name string, definition model.Bindable) *ParseError {
+	return &ParseError{
+		Kind:       InvalidValue,
+		Token:      value,
+		Index:      -1,
+		Definition: name,
+		Source:     "environment variable " + definition.GetEnvironmentVariable(),
+	}
+}
+
+func getTokenSource(token source.Token) model.Source {
+	var result = model.NewCommandLineSource(token.Index)
+	if token.Path != "" {
+		result = model.NewResponseFileSource(token.Index, token.Path, token.Line)
+	}
+	return result
+}
+
+func getTokenValues(tokens []source.Token) []string {
+	var result = []string{}
+	for _, token := range tokens {
+		result = append(result, token.Value)
+	}
+	return result
+}
+
+func getTokenLocation(token source.Token) string {
+	var result = ""
+	if token.Path != "" {
+		result = fmt.Sprintf("%s:%d", token.Path, token.Line)
+	}
+	return result
+}
+
+func newResponseFileError(err error) *ParseError {
+	var result = &ParseError{Kind: InvalidResponseFile, Token: err.Error(), Index: -1}
+	if expansionError, isExpansionError := err.(*source.ExpansionError); isExpansionError {
+		result.Token = expansionError.Message
+		result.Index = expansionError.Index
+		result.Source = getTokenLocation(source.Token{Path: expansionError.Path, Line: expansionError.Line})
+	}
+	return result
+}
+
+func getConfigFileLocation(source model.Source) string {
+	return fmt.Sprintf("%s:%d", source.Name, source.Line)
+}
+
+func newConfigFileError(path string, err error) *ParseError {
+	var result = &ParseError{Kind: InvalidConfig, Token: err.Error(), Index: -1}
+	if syntaxError, isSyntaxError := err.(*source.SyntaxError); isSyntaxError {
+		result.Token = syntaxError.Message
+		result.Source = fmt.Sprintf("%s:%d", path, syntaxError.Line)
+	}
+	return result
+}
+
+func isValidEnvironmentVariable(name string) bool {
+	return configuration.EnvironmentVariablePattern.MatchString(name)
+}
+
 func isValidRegularExpression(pattern string) bool {
 	_, err := regexp.Compile(pattern)
 	return err == nil
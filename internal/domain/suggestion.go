@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/echsylon/go-args/internal/configuration"
+)
+
+const maxSuggestionDistance = 2
+
+// findSuggestion returns the defined name closest to the offending token of
+// the error, formatted the way the caller would type it, or an empty string
+// if nothing is close enough.
+func (state *stateMachine) findSuggestion(err *ParseError) string {
+	var result = ""
+	var scope = state
+	var help *stateMachine = nil
+	if state.isHelp {
+		scope = state.helpTarget
+		help = state
+	}
+
+	switch {
+	case err.Kind == UnknownOption && strings.HasPrefix(err.Token, "-"):
+		result = findClosestName(getOptionTokenName(err.Token), err.Token, state.getOptionCandidates())
+	case err.Kind == UnknownOption:
+		result = findClosestName(err.Token, err.Token, state.getConfigKeyCandidates())
+	case err.Kind == UnknownCommand:
+		result = findClosestName(err.Token, err.Token, scope.getCommandCandidates(help))
+	case err.Kind == UnmatchedValue && err.Definition == "" && !strings.HasPrefix(err.Token, "-"):
+		result = findClosestName(err.Token, err.Token, state.getOptionCandidates())
+	}
+	return result
+}
+
+// getOptionCandidates maps the option names, without dashes, to the way
+// they're given on the command line.
+func (state *stateMachine) getOptionCandidates() map[string]string {
+	var result = make(map[string]string)
+	for _, option := range state.data.GetOptions() {
+		if name := option.GetShortName(); name != "" {
+			result[name] = "-" + name
+		}
+		if name := option.GetLongName(); name != "" {
+			result[name] = "--" + name
+		}
+		if name := option.GetLongName(); name != "" && option.IsNegatable() {
+			negated := configuration.NegatedOptionPrefix + name
+			result[negated] = "--" + negated
+		}
+	}
+	return result
+}
+
+func (state *stateMachine) getConfigKeyCandidates() map[string]string {
+	var result = make(map[string]string)
+	for _, option := range state.data.GetOptions() {
+		if name := getOptionName(option); name != "" {
+			result[name] = name
+		}
+	}
+	for _, argument := range state.data.GetArguments() {
+		result[argument.GetName()] = argument.GetName()
+	}
+	return result
+}
+
+// getCommandCandidates maps the command names and aliases to themselves.
+// Given the help command in use, the topics are included as well, while
+// other help commands are left out, since the help command rejects them.
+func (state *stateMachine) getCommandCandidates(help *stateMachine) map[string]string {
+	var result = make(map[string]string)
+	for _, command := range state.commands {
+		if help == nil || !command.isHelp || command == help {
+			result[command.name] = command.name
+			for _, alias := range command.aliases {
+				result[alias] = alias
+			}
+		}
+	}
+	for _, topic := range state.topics {
+		if help != nil {
+			result[topic.GetName()] = topic.GetName()
+		}
+	}
+	return result
+}
+
+func getOptionTokenName(input string) string {
+	var result = strings.TrimLeft(input, "-")
+	if index := strings.Index(result, "="); index >= 0 {
+		result = result[:index]
+	}
+	return result
+}
+
+// findClosestName returns the candidate with the smallest edit distance to
+// the name, if within reach, but never the offending token itself. Ties are
+// broken alphabetically to keep the suggestion stable.
+func findClosestName(name string, token string, candidates map[string]string) string {
+	var result = ""
+	var closest = ""
+	var closestDistance = maxSuggestionDistance + 1
+	for candidate, display := range candidates {
+		distance := getEditDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if display != token && distance < len(name) && (distance < closestDistance || distance == closestDistance && candidate < closest) {
+			result = display
+			closest = candidate
+			closestDistance = distance
+		}
+	}
+	return result
+}
+
+// getEditDistance computes the optimal string alignment distance, i.e. the
+// number of single character insertions, deletions, substitutions and
+// transpositions of adjacent characters needed to turn one string into the
+// other.
+func getEditDistance(first string, second string) int {
+	rows := make([][]int, len(first)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(second)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(first); i++ {
+		for j := 1; j <= len(second); j++ {
+			cost := 1
+			if first[i-1] == second[j-1] {
+				cost = 0
+			}
+			rows[i][j] = minInt(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && first[i-1] == second[j-2] && first[i-2] == second[j-1] {
+				rows[i][j] = minInt(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(first)][len(second)]
+}
+
+func minInt(values ...int) int {
+	var result = values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
@@ -2,10 +2,14 @@ package model
 
 type Argument interface {
 	Constrainable
+	Bindable
 
 	GetName() string
 	GetDescription() string
 	ExpectsMultipleValues() bool
+	GetDefaultValues() []string
+	SetDefaultValues(values []string)
+	HasDefaultValues() bool
 }
 
 func NewArgument(name string, description string, minCount int, maxCount int, pattern string) Argument {
@@ -23,6 +27,9 @@ type argument struct {
 	pattern     string
 	name        string
 	description string
+	defaults    []string
+	environment string
+	prefix      string
 }
 
 // Constrainable interface
@@ -30,7 +37,22 @@ func (a *argument) GetMaxValuesCount() int { return a.maxCount }
 func (a *argument) GetMinValuesCount() int { return a.minCount }
 func (a *argument) GetPattern() string     { return a.pattern }
 
+// Bindable interface
+func (a *argument) GetEnvironmentVariable() string {
+	result := ""
+	if a.environment != "" {
+		result = a.prefix + a.environment
+	}
+	return result
+}
+
+func (a *argument) SetEnvironmentVariable(name string) { a.environment = name }
+func (a *argument) SetEnvironmentPrefix(prefix string) { a.prefix = prefix }
+
 // Argument interface
-func (a *argument) GetName() string             { return a.name }
-func (a *argument) GetDescription() string      { return a.description }
-func (a *argument) ExpectsMultipleValues() bool { return a.maxCount > 1 }
+func (a *argument) GetName() string                  { return a.name }
+func (a *argument) GetDescription() string           { return a.description }
+func (a *argument) ExpectsMultipleValues() bool      { return a.maxCount > 1 }
+func (a *argument) GetDefaultValues() []string       { return a.defaults }
+func (a *argument) SetDefaultValues(values []string) { a.defaults = values }
+func (a *argument) HasDefaultValues() bool           { return len(a.defaults) > 0 }
@@ -0,0 +1,7 @@
+package model
+
+type Bindable interface {
+	GetEnvironmentVariable() string
+	SetEnvironmentVariable(name string)
+	SetEnvironmentPrefix(prefix string)
+}
@@ -0,0 +1,7 @@
+package model
+
+type Command interface {
+	GetName() string
+	GetDescription() string
+	GetAliases() []string
+}
@@ -1,14 +1,27 @@
 package model
 
+import "github.com/echsylon/go-args/internal/configuration"
+
 type Option interface {
 	Constrainable
+	Bindable
 
 	IsParsed() bool
 	SetParsed()
+	ClearParsed()
+	GetParsedCount() int
 	IsHelpTrigger() bool
+	IsCounter() bool
+	IsNegatable() bool
+	IsRepeatable() bool
 	GetShortName() string
 	GetLongName() string
 	GetDescription() string
+	GetDefaultValue() string
+	SetDefaultValue(value string)
+	HasDefaultValue() bool
+	IsRequired() bool
+	SetRequired()
 }
 
 func NewOption(shortName string, longName string, description string, pattern string) Option {
@@ -17,40 +30,116 @@ func NewOption(shortName string, longName string, description string, pattern st
 		longName:    longName,
 		pattern:     pattern,
 		description: description,
-		parsed:      false,
+		minCount:    0,
+		maxCount:    1,
+		parsed:      0,
+		help:        false,
+	}
+}
+
+func NewRepeatableOption(shortName string, longName string, description string, minCount int, maxCount int, pattern string) Option {
+	return &option{
+		shortName:   shortName,
+		longName:    longName,
+		pattern:     pattern,
+		description: description,
+		minCount:    minCount,
+		maxCount:    maxCount,
+		parsed:      0,
 		help:        false,
 	}
 }
 
+func NewCounterOption(shortName string, longName string, description string) Option {
+	return &option{
+		shortName:   shortName,
+		longName:    longName,
+		pattern:     "",
+		description: description,
+		minCount:    0,
+		maxCount:    configuration.UnboundedCount,
+		parsed:      0,
+		help:        false,
+		counter:     true,
+	}
+}
+
+func NewBooleanOption(shortName string, longName string, description string) Option {
+	return &option{
+		shortName:   shortName,
+		longName:    longName,
+		pattern:     configuration.BooleanValuePattern,
+		description: description,
+		minCount:    0,
+		maxCount:    configuration.UnboundedCount,
+		parsed:      0,
+		help:        false,
+		negatable:   true,
+	}
+}
+
 func NewHelpOption(shortName string, longName string, description string) Option {
 	return &option{
 		shortName:   shortName,
 		longName:    longName,
 		pattern:     "",
 		description: description,
-		parsed:      false,
+		minCount:    0,
+		maxCount:    1,
+		parsed:      0,
 		help:        true,
 	}
 }
 
 type option struct {
-	shortName   string
-	longName    string
-	pattern     string
-	description string
-	parsed      bool
-	help        bool
+	shortName    string
+	longName     string
+	pattern      string
+	description  string
+	minCount     int
+	maxCount     int
+	parsed       int
+	help         bool
+	counter      bool
+	negatable    bool
+	defaultValue string
+	hasDefault   bool
+	required     bool
+	environment  string
+	prefix       string
 }
 
 // Constrainable interface
-func (o *option) GetMinValuesCount() int { return 0 }
-func (o *option) GetMaxValuesCount() int { return 1 }
+func (o *option) GetMinValuesCount() int { return o.minCount }
+func (o *option) GetMaxValuesCount() int { return o.maxCount }
 func (o *option) GetPattern() string     { return o.pattern }
 
+// Bindable interface
+func (o *option) GetEnvironmentVariable() string {
+	result := ""
+	if o.environment != "" {
+		result = o.prefix + o.environment
+	}
+	return result
+}
+
+func (o *option) SetEnvironmentVariable(name string) { o.environment = name }
+func (o *option) SetEnvironmentPrefix(prefix string) { o.prefix = prefix }
+
 // Option interface
-func (o *option) IsParsed() bool         { return o.parsed }
-func (o *option) SetParsed()             { o.parsed = true }
-func (o *option) IsHelpTrigger() bool    { return o.help }
-func (o *option) GetShortName() string   { return o.shortName }
-func (o *option) GetLongName() string    { return o.longName }
-func (o *option) GetDescription() string { return o.description }
+func (o *option) IsParsed() bool               { return o.parsed > 0 }
+func (o *option) SetParsed()                   { o.parsed++ }
+func (o *option) ClearParsed()                 { o.parsed = 0 }
+func (o *option) GetParsedCount() int          { return o.parsed }
+func (o *option) IsHelpTrigger() bool          { return o.help }
+func (o *option) IsCounter() bool              { return o.counter }
+func (o *option) IsNegatable() bool            { return o.negatable }
+func (o *option) IsRepeatable() bool           { return o.maxCount > 1 }
+func (o *option) GetShortName() string         { return o.shortName }
+func (o *option) GetLongName() string          { return o.longName }
+func (o *option) GetDescription() string       { return o.description }
+func (o *option) GetDefaultValue() string      { return o.defaultValue }
+func (o *option) SetDefaultValue(value string) { o.defaultValue = value; o.hasDefault = true }
+func (o *option) HasDefaultValue() bool        { return o.hasDefault }
+func (o *option) IsRequired() bool             { return o.required }
+func (o *option) SetRequired()                 { o.required = true }
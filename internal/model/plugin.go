@@ -0,0 +1,26 @@
+package model
+
+type Plugin interface {
+	GetName() string
+	GetPath() string
+}
+
+func NewPlugin(name string, path string) Plugin {
+	return &plugin{
+		name: name,
+		path: path,
+	}
+}
+
+type plugin struct {
+	name string
+	path string
+}
+
+func (plugin *plugin) GetName() string {
+	return plugin.name
+}
+
+func (plugin *plugin) GetPath() string {
+	return plugin.path
+}
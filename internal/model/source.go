@@ -0,0 +1,79 @@
+package model
+
+import "fmt"
+
+type SourceKind int
+
+const (
+	NotGiven SourceKind = iota
+	CommandLine
+	EnvironmentVariable
+	ConfigFile
+	DefaultValue
+	ResponseFile
+)
+
+type Source struct {
+	Kind  SourceKind
+	Index int
+	Name  string
+	Line  int
+}
+
+func NewCommandLineSource(index int) Source {
+	return Source{Kind: CommandLine, Index: index}
+}
+
+func NewEnvironmentSource(variable string) Source {
+	return Source{Kind: EnvironmentVariable, Index: -1, Name: variable}
+}
+
+func NewConfigFileSource(path string, line int) Source {
+	return Source{Kind: ConfigFile, Index: -1, Name: path, Line: line}
+}
+
+func NewResponseFileSource(index int, path string, line int) Source {
+	return Source{Kind: ResponseFile, Index: index, Name: path, Line: line}
+}
+
+func NewDefaultSource() Source {
+	return Source{Kind: DefaultValue, Index: -1}
+}
+
+func (kind SourceKind) String() string {
+	switch kind {
+	case NotGiven:
+		return "NotGiven"
+	case CommandLine:
+		return "CommandLine"
+	case EnvironmentVariable:
+		return "EnvironmentVariable"
+	case ConfigFile:
+		return "ConfigFile"
+	case DefaultValue:
+		return "DefaultValue"
+	case ResponseFile:
+		return "ResponseFile"
+	default:
+		return fmt.Sprintf("SourceKind(%d)", int(kind))
+	}
+}
+
+func (source Source) String() string {
+	var result string
+	switch source.Kind {
+	case CommandLine:
+		result = fmt.Sprintf("command line, index %d", source.Index)
+	case EnvironmentVariable:
+		result = "environment variable " + source.Name
+	case ConfigFile:
+		result = fmt.Sprintf("config file %s:%d", source.Name, source.Line)
+	case DefaultValue:
+		result = "default value"
+	case ResponseFile:
+		result = fmt.Sprintf("response file %s:%d", source.Name, source.Line)
+	default:
+		result = "not given"
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package model
+
+type Topic interface {
+	GetName() string
+	GetDescription() string
+	GetText() string
+}
+
+func NewTopic(name string, description string, text string) Topic {
+	return &topic{
+		name:        name,
+		description: description,
+		text:        text,
+	}
+}
+
+type topic struct {
+	name        string
+	description string
+	text        string
+}
+
+func (topic *topic) GetName() string {
+	return topic.name
+}
+
+func (topic *topic) GetDescription() string {
+	return topic.description
+}
+
+func (topic *topic) GetText() string {
+	return topic.text
+}
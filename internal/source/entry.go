@@ -0,0 +1,52 @@
+package source
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+type Entry struct {
+	Key    string
+	Values []string
+	Line   int
+}
+
+type SyntaxError struct {
+	Line    int
+	Message string
+}
+
+func (err *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", err.Line, err.Message)
+}
+
+// ReadFile reads the entries of a config file. The format is decided by the
+// file extension: ".ini" and ".toml" files are read as such, anything else
+// is read as JSON.
+func ReadFile(path string) ([]Entry, error) {
+	var result []Entry = nil
+	content, err := ioutil.ReadFile(path)
+	if err == nil {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ini":
+			result, err = ReadINI(content)
+		case ".toml":
+			result, err = ReadTOML(content)
+		default:
+			result, err = ReadJSON(content)
+		}
+	}
+	return result, err
+}
+
+func getLineNumber(content []byte, offset int64) int {
+	var result = 1
+	for index := int64(0); index < offset && index < int64(len(content)); index++ {
+		if content[index] == '\n' {
+			result++
+		}
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package source
+
+import "strings"
+
+// ReadINI reads `key = value` pairs, optionally grouped in `[section]`
+// blocks. Keys within a section are prefixed with the section name, e.g.
+// `server.port`. A key given several times collects all its values.
+func ReadINI(content []byte) ([]Entry, error) {
+	var result []Entry
+	var err error = nil
+	var section = ""
+	indices := make(map[string]int)
+
+	for index, text := range strings.Split(string(content), "\n") {
+		line := strings.TrimSpace(text)
+		if strings.HasPrefix(line, "[") {
+			section, err = readINISection(line, index+1)
+		} else if isINIContent(line) {
+			result, err = appendINIPair(result, indices, section, line, index+1)
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	return result, err
+}
+
+func isINIContent(line string) bool {
+	return line != "" && !strings.HasPrefix(line, ";") && !strings.HasPrefix(line, "#")
+}
+
+func appendINIPair(entries []Entry, indices map[string]int, section string, line string, number int) ([]Entry, error) {
+	var err error = nil
+	if key, value, isValid := splitINIPair(line); !isValid {
+		err = &SyntaxError{Line: number, Message: "expected key = value"}
+	} else {
+		if section != "" {
+			key = section + "." + key
+		}
+		if position, isKnown := indices[key]; isKnown {
+			entries[position].Values = append(entries[position].Values, value)
+		} else {
+			indices[key] = len(entries)
+			entries = append(entries, Entry{Key: key, Values: []string{value}, Line: number})
+		}
+	}
+	return entries, err
+}
+
+func readINISection(line string, number int) (string, error) {
+	var result = ""
+	var err error = nil
+	if !strings.HasSuffix(line, "]") {
+		err = &SyntaxError{Line: number, Message: "expected ] after section name"}
+	} else {
+		result = strings.TrimSpace(line[1 : len(line)-1])
+	}
+	return result, err
+}
+
+func splitINIPair(line string) (string, string, bool) {
+	var key = ""
+	var value = ""
+	var isValid = false
+	if index := strings.Index(line, "="); index > 0 {
+		key = strings.TrimSpace(line[:index])
+		value = unquoteINIValue(strings.TrimSpace(line[index+1:]))
+		isValid = key != ""
+	}
+	return key, value, isValid
+}
+
+func unquoteINIValue(value string) string {
+	var result = value
+	if len(value) >= 2 {
+		first := value[0]
+		last := value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			result = value[1 : len(value)-1]
+		}
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package source
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ReadJSON reads a flat JSON object where each key maps to a string, number
+// or boolean value, or to an array of such values.
+func ReadJSON(content []byte) ([]Entry, error) {
+	var result []Entry
+	var err error = nil
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	if err = expectDelimiter(decoder, '{'); err == nil {
+		keys := make(map[string]bool)
+		for err == nil && decoder.More() {
+			var entry Entry
+			if entry, err = readJSONEntry(decoder, content); err == nil && keys[entry.Key] {
+				err = &SyntaxError{Line: entry.Line, Message: "duplicate key: " + entry.Key}
+			} else if err == nil {
+				keys[entry.Key] = true
+				result = append(result, entry)
+			}
+		}
+	}
+
+	if err == nil {
+		err = expectDelimiter(decoder, '}')
+	}
+
+	if err == nil && decoder.More() {
+		err = &SyntaxError{Line: getLineNumber(content, decoder.InputOffset()), Message: "unexpected content after object"}
+	}
+
+	return result, toSyntaxError(err, content, decoder)
+}
+
+func readJSONEntry(decoder *json.Decoder, content []byte) (Entry, error) {
+	var entry Entry
+	token, err := decoder.Token()
+	if err == nil {
+		entry.Key = fmt.Sprint(token)
+		entry.Line = getLineNumber(content, decoder.InputOffset())
+		token, err = decoder.Token()
+	}
+
+	if err == nil && token == json.Delim('[') {
+		entry.Values, err = readJSONArray(decoder, entry)
+	} else if err == nil {
+		var value string
+		value, err = toJSONValue(token, entry)
+		entry.Values = []string{value}
+	}
+
+	return entry, err
+}
+
+func readJSONArray(decoder *json.Decoder, entry Entry) ([]string, error) {
+	var result []string
+	var err error = nil
+	for err == nil && decoder.More() {
+		var token json.Token
+		var value string
+		if token, err = decoder.Token(); err == nil {
+			value, err = toJSONValue(token, entry)
+			result = append(result, value)
+		}
+	}
+
+	if err == nil {
+		err = expectDelimiter(decoder, ']')
+	}
+
+	return result, err
+}
+
+func toJSONValue(token json.Token, entry Entry) (string, error) {
+	var result = ""
+	var err error = nil
+	switch value := token.(type) {
+	case string:
+		result = value
+	case json.Number:
+		result = value.String()
+	case bool:
+		result = fmt.Sprint(value)
+	default:
+		err = &SyntaxError{Line: entry.Line, Message: "unsupported value for key: " + entry.Key}
+	}
+	return result, err
+}
+
+func expectDelimiter(decoder *json.Decoder, delimiter json.Delim) error {
+	token, err := decoder.Token()
+	if err == nil && token != delimiter {
+		err = fmt.Errorf("expected %s", delimiter)
+	}
+	return err
+}
+
+func toSyntaxError(err error, content []byte, decoder *json.Decoder) error {
+	var result = err
+	if syntaxError, isJSONError := err.(*json.SyntaxError); isJSONError {
+		result = &SyntaxError{Line: getLineNumber(content, syntaxError.Offset), Message: syntaxError.Error()}
+	} else if err == io.EOF || err == io.ErrUnexpectedEOF {
+		result = &SyntaxError{Line: getLineNumber(content, decoder.InputOffset()), Message: "unexpected end of input"}
+	} else if _, isSyntaxError := err.(*SyntaxError); err != nil && !isSyntaxError {
+		result = &SyntaxError{Line: getLineNumber(content, decoder.InputOffset()), Message: err.Error()}
+	}
+	return result
+}
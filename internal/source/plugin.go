@@ -0,0 +1,73 @@
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// FindPlugin looks for an executable named prefix + name in the given
+// directories, in order, and returns the path of the first one found.
+func FindPlugin(prefix string, name string, directories []string) (string, bool) {
+	var result = ""
+	var isFound = false
+	for _, directory := range directories {
+		path := filepath.Join(directory, prefix+name+getExecutableSuffix())
+		if isExecutable(path) {
+			result = path
+			isFound = true
+			break
+		}
+	}
+	return result, isFound
+}
+
+// FindPlugins lists the executables in the given directories whose names
+// start with the prefix. An executable in an earlier directory shadows any
+// executable with the same name in a later one, just like on PATH. The
+// result is sorted by name.
+func FindPlugins(prefix string, directories []string) []Plugin {
+	var result []Plugin
+	var names = make(map[string]bool)
+	for _, directory := range directories {
+		files, _ := ioutil.ReadDir(directory)
+		for _, file := range files {
+			name := strings.TrimSuffix(file.Name(), getExecutableSuffix())
+			path := filepath.Join(directory, file.Name())
+			if strings.HasSuffix(file.Name(), getExecutableSuffix()) && strings.HasPrefix(name, prefix) && len(name) > len(prefix) && !names[name] && isExecutable(path) {
+				names[name] = true
+				result = append(result, Plugin{Name: name[len(prefix):], Path: path})
+			}
+		}
+	}
+
+	sort.Slice(result, func(i int, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// GetPathDirectories returns the directories of the PATH environment
+// variable, in lookup order.
+func GetPathDirectories() []string {
+	return filepath.SplitList(os.Getenv("PATH"))
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && (runtime.GOOS == "windows" || info.Mode()&0111 != 0)
+}
+
+func getExecutableSuffix() string {
+	var result = ""
+	if runtime.GOOS == "windows" {
+		result = ".exe"
+	}
+	return result
+}
@@ -0,0 +1,176 @@
+package source
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+type Token struct {
+	Value string
+	Index int
+	Path  string
+	Line  int
+}
+
+type ExpansionError struct {
+	Index   int
+	Path    string
+	Line    int
+	Message string
+}
+
+func (err *ExpansionError) Error() string {
+	var result = err.Message
+	if err.Path != "" {
+		result = fmt.Sprintf("%s:%d: %s", err.Path, err.Line, err.Message)
+	}
+	return result
+}
+
+func NewTokens(input []string) []Token {
+	var result []Token
+	for index, value := range input {
+		result = append(result, Token{Value: value, Index: index})
+	}
+	return result
+}
+
+// ExpandResponseFiles replaces each `@path` input token with the tokens read
+// from that file, recursively. Expansion stops at the end-of-options token,
+// which allows passing literal values starting with `@`.
+func ExpandResponseFiles(input []string, endOfOptionsToken string) ([]Token, error) {
+	var err error = nil
+	state := &expansion{endOfOptionsToken: endOfOptionsToken}
+	for index, value := range input {
+		if err = state.expand(Token{Value: value, Index: index}); err != nil {
+			break
+		}
+	}
+	return state.tokens, err
+}
+
+type expansion struct {
+	endOfOptionsToken string
+	isEndOfOptions    bool
+	tokens            []Token
+	paths             []string
+	files             []string
+}
+
+func (state *expansion) expand(token Token) error {
+	var err error = nil
+	if !state.isEndOfOptions && len(token.Value) > 1 && strings.HasPrefix(token.Value, "@") {
+		err = state.expandFile(token.Value[1:], token)
+	} else {
+		state.isEndOfOptions = state.isEndOfOptions || token.Value == state.endOfOptionsToken
+		state.tokens = append(state.tokens, token)
+	}
+	return err
+}
+
+func (state *expansion) expandFile(path string, origin Token) error {
+	var err error = nil
+	var content []byte
+	absolutePath, _ := filepath.Abs(path)
+	if containsString(state.paths, absolutePath) {
+		chain := strings.Join(append(state.files, path), " -> ")
+		err = &ExpansionError{Index: origin.Index, Path: origin.Path, Line: origin.Line, Message: "response file cycle: " + chain}
+	} else if content, err = ioutil.ReadFile(path); err != nil {
+		err = &ExpansionError{Index: origin.Index, Path: origin.Path, Line: origin.Line, Message: err.Error()}
+	} else if words, syntaxError := splitResponseFile(string(content)); syntaxError != nil {
+		err = &ExpansionError{Index: origin.Index, Path: path, Line: syntaxError.Line, Message: syntaxError.Message}
+	} else {
+		state.paths = append(state.paths, absolutePath)
+		state.files = append(state.files, path)
+		for _, word := range words {
+			if err = state.expand(Token{Value: word.value, Index: origin.Index, Path: path, Line: word.line}); err != nil {
+				break
+			}
+		}
+		state.paths = state.paths[:len(state.paths)-1]
+		state.files = state.files[:len(state.files)-1]
+	}
+	return err
+}
+
+type responseWord struct {
+	value string
+	line  int
+}
+
+// splitResponseFile splits the content into words the way a POSIX shell
+// would: words are separated by whitespace, single quotes preserve their
+// content literally, double quotes allow escaping `"` and `\`, a backslash
+// outside quotes escapes the next character and a `#` starting a word
+// comments out the rest of the line.
+func splitResponseFile(content string) ([]responseWord, *SyntaxError) {
+	var result []responseWord
+	var err *SyntaxError = nil
+	var word strings.Builder
+	var isInWord = false
+	var quote byte = 0
+	var line = 1
+	var wordLine = 1
+
+	for index := 0; index < len(content); index++ {
+		char := content[index]
+		if char == '\n' {
+			line++
+		}
+
+		if quote == '\'' && char == '\'' || quote == '"' && char == '"' {
+			quote = 0
+		} else if quote == '"' && char == '\\' && index+1 < len(content) && strings.IndexByte("\"\\", content[index+1]) >= 0 {
+			index++
+			word.WriteByte(content[index])
+		} else if quote != 0 {
+			word.WriteByte(char)
+		} else if char == ' ' || char == '\t' || char == '\r' || char == '\n' {
+			if isInWord {
+				result = append(result, responseWord{value: word.String(), line: wordLine})
+				word.Reset()
+				isInWord = false
+			}
+		} else if char == '\\' && index+1 < len(content) && content[index+1] == '\n' {
+			index++
+			line++
+		} else if char == '#' && !isInWord {
+			for index+1 < len(content) && content[index+1] != '\n' {
+				index++
+			}
+		} else {
+			if !isInWord {
+				isInWord = true
+				wordLine = line
+			}
+			if char == '\'' || char == '"' {
+				quote = char
+			} else if char == '\\' && index+1 < len(content) {
+				index++
+				word.WriteByte(content[index])
+			} else {
+				word.WriteByte(char)
+			}
+		}
+	}
+
+	if quote != 0 {
+		err = &SyntaxError{Line: wordLine, Message: "unterminated quote"}
+	} else if isInWord {
+		result = append(result, responseWord{value: word.String(), line: wordLine})
+	}
+	return result, err
+}
+
+func containsString(values []string, value string) bool {
+	var result = false
+	for _, item := range values {
+		if item == value {
+			result = true
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,267 @@
+package source
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var tomlBareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+`)
+var tomlIntegerPattern = regexp.MustCompile(`^[+-]?[0-9](_?[0-9])*$`)
+var tomlFloatPattern = regexp.MustCompile(`^[+-]?[0-9](_?[0-9])*(\.[0-9](_?[0-9])*)?([eE][+-]?[0-9](_?[0-9])*)?$`)
+
+// ReadTOML reads a subset of TOML: tables (`[server]`), bare, quoted and
+// dotted keys, basic and literal single line strings, integers, floats,
+// booleans and arrays of those. Keys within a table are prefixed with the
+// table name, e.g. `server.port`. Anything else is reported as unsupported.
+func ReadTOML(content []byte) ([]Entry, error) {
+	var result []Entry
+	var err error = nil
+	var table = ""
+	keys := make(map[string]bool)
+	scanner := &tomlScanner{content: content, line: 1}
+
+	for scanner.skipBlankLines(); err == nil && !scanner.isDone(); scanner.skipBlankLines() {
+		if scanner.peek() == '[' {
+			table, err = scanner.readTable()
+		} else {
+			var entry Entry
+			if entry, err = scanner.readEntry(table); err == nil && keys[entry.Key] {
+				err = &SyntaxError{Line: entry.Line, Message: "duplicate key: " + entry.Key}
+			} else if err == nil {
+				keys[entry.Key] = true
+				result = append(result, entry)
+			}
+		}
+	}
+
+	return result, err
+}
+
+type tomlScanner struct {
+	content []byte
+	offset  int
+	line    int
+}
+
+func (scanner *tomlScanner) isDone() bool {
+	return scanner.offset >= len(scanner.content)
+}
+
+func (scanner *tomlScanner) peek() byte {
+	var result byte = 0
+	if !scanner.isDone() {
+		result = scanner.content[scanner.offset]
+	}
+	return result
+}
+
+func (scanner *tomlScanner) next() byte {
+	result := scanner.peek()
+	if !scanner.isDone() {
+		scanner.offset++
+	}
+	if result == '\n' {
+		scanner.line++
+	}
+	return result
+}
+
+func (scanner *tomlScanner) skipSpaces() {
+	for scanner.peek() == ' ' || scanner.peek() == '\t' {
+		scanner.next()
+	}
+}
+
+func (scanner *tomlScanner) skipComment() {
+	if scanner.peek() == '#' {
+		for !scanner.isDone() && scanner.peek() != '\n' {
+			scanner.next()
+		}
+	}
+}
+
+func (scanner *tomlScanner) skipBlankLines() {
+	for scanner.skipSpaces(); !scanner.isDone(); scanner.skipSpaces() {
+		scanner.skipComment()
+		if next := scanner.peek(); next == '\n' || next == '\r' {
+			scanner.next()
+		} else if !scanner.isDone() {
+			break
+		}
+	}
+}
+
+func (scanner *tomlScanner) newError(message string) error {
+	return &SyntaxError{Line: scanner.line, Message: message}
+}
+
+func (scanner *tomlScanner) expectLineEnd() error {
+	var err error = nil
+	scanner.skipSpaces()
+	scanner.skipComment()
+	if scanner.peek() == '\r' {
+		scanner.next()
+	}
+	if !scanner.isDone() && scanner.next() != '\n' {
+		err = scanner.newError("expected end of line")
+	}
+	return err
+}
+
+func (scanner *tomlScanner) readTable() (string, error) {
+	var name = ""
+	var err error = nil
+	scanner.next()
+	if scanner.peek() == '[' {
+		err = scanner.newError("unsupported array of tables")
+	} else if name, err = scanner.readKey(); err == nil {
+		scanner.skipSpaces()
+		if scanner.next() != ']' {
+			err = scanner.newError("expected ] after table name")
+		} else {
+			err = scanner.expectLineEnd()
+		}
+	}
+	return name, err
+}
+
+func (scanner *tomlScanner) readEntry(table string) (Entry, error) {
+	var entry = Entry{Line: scanner.line}
+	key, err := scanner.readKey()
+	if err == nil {
+		scanner.skipSpaces()
+		if scanner.next() != '=' {
+			err = scanner.newError("expected = after key")
+		}
+	}
+
+	if err == nil {
+		scanner.skipSpaces()
+		if scanner.peek() == '[' {
+			entry.Values, err = scanner.readArray()
+		} else {
+			var value string
+			value, err = scanner.readValue()
+			entry.Values = []string{value}
+		}
+	}
+
+	if err == nil {
+		err = scanner.expectLineEnd()
+	}
+
+	entry.Key = key
+	if table != "" {
+		entry.Key = table + "." + key
+	}
+	return entry, err
+}
+
+func (scanner *tomlScanner) readKey() (string, error) {
+	var parts []string
+	var err error = nil
+	for isDotted := true; err == nil && isDotted; isDotted = scanner.peek() == '.' {
+		var part string
+		if len(parts) > 0 {
+			scanner.next()
+		}
+		scanner.skipSpaces()
+		if next := scanner.peek(); next == '"' || next == '\'' {
+			part, err = scanner.readString()
+		} else if match := tomlBareKeyPattern.Find(scanner.content[scanner.offset:]); match != nil {
+			part = string(match)
+			scanner.offset += len(match)
+		} else {
+			err = scanner.newError("expected key")
+		}
+		parts = append(parts, part)
+		scanner.skipSpaces()
+	}
+	return strings.Join(parts, "."), err
+}
+
+func (scanner *tomlScanner) readArray() ([]string, error) {
+	var result []string
+	var err error = nil
+	scanner.next()
+	for scanner.skipBlankLines(); err == nil && scanner.peek() != ']'; scanner.skipBlankLines() {
+		var value string
+		if value, err = scanner.readValue(); err == nil {
+			result = append(result, value)
+			scanner.skipBlankLines()
+			if scanner.peek() == ',' {
+				scanner.next()
+			} else if scanner.peek() != ']' {
+				err = scanner.newError("expected , or ] in array")
+			}
+		}
+	}
+
+	if err == nil {
+		scanner.next()
+	}
+	return result, err
+}
+
+func (scanner *tomlScanner) readValue() (string, error) {
+	var result = ""
+	var err error = nil
+	switch scanner.peek() {
+	case '"', '\'':
+		result, err = scanner.readString()
+	case '[':
+		err = scanner.newError("unsupported nested array")
+	case '{':
+		err = scanner.newError("unsupported inline table")
+	default:
+		result, err = scanner.readBareValue()
+	}
+	return result, err
+}
+
+func (scanner *tomlScanner) readString() (string, error) {
+	var result = ""
+	var err error = nil
+	quote := scanner.next()
+	start := scanner.offset
+	if scanner.peek() == quote && scanner.offset+1 < len(scanner.content) && scanner.content[scanner.offset+1] == quote {
+		err = scanner.newError("unsupported multi-line string")
+	} else {
+		for !scanner.isDone() && scanner.peek() != quote && scanner.peek() != '\n' {
+			if scanner.next() == '\\' && quote == '"' {
+				scanner.next()
+			}
+		}
+		if scanner.peek() != quote {
+			err = scanner.newError("unterminated string")
+		} else if text := string(scanner.content[start:scanner.offset]); quote == '\'' {
+			result = text
+		} else if result, err = strconv.Unquote(`"` + text + `"`); err != nil {
+			err = scanner.newError("invalid string escape")
+		}
+		scanner.next()
+	}
+	return result, err
+}
+
+func (scanner *tomlScanner) readBareValue() (string, error) {
+	var result = ""
+	var err error = nil
+	start := scanner.offset
+	for !scanner.isDone() && !strings.ContainsRune(" \t\r\n,]#", rune(scanner.peek())) {
+		scanner.next()
+	}
+
+	text := string(scanner.content[start:scanner.offset])
+	if text == "true" || text == "false" {
+		result = text
+	} else if tomlIntegerPattern.MatchString(text) || tomlFloatPattern.MatchString(text) {
+		result = strings.TrimPrefix(strings.Replace(text, "_", "", -1), "+")
+	} else if text == "" {
+		err = scanner.newError("expected value")
+	} else {
+		err = scanner.newError("unsupported value: " + text)
+	}
+	return result, err
+}
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/echsylon/go-args/internal/model"
+)
+
+type ValueRecord struct {
+	Name    string
+	Values  []string
+	Sources []model.Source
+}
+
+func GetValuesDump(records []ValueRecord) string {
+	var lines []string
+	nameWidth, valueWidth := calculateValueRecordColumnWidths(records)
+	for _, record := range records {
+		for index := 0; index == 0 || index < len(record.Values); index++ {
+			name := ""
+			if index == 0 {
+				name = record.Name
+			}
+			line := fmt.Sprintf("%-*s  ", nameWidth, name)
+			if valueWidth > 0 {
+				line += fmt.Sprintf("%-*s  ", valueWidth, getRecordValue(record, index))
+			}
+			line += "(" + getRecordSource(record, index).String() + ")"
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func getRecordValue(record ValueRecord, index int) string {
+	var result = ""
+	if index < len(record.Values) {
+		result = record.Values[index]
+	}
+	return result
+}
+
+func getRecordSource(record ValueRecord, index int) model.Source {
+	var result = model.Source{Kind: model.NotGiven, Index: -1}
+	if index < len(record.Sources) {
+		result = record.Sources[index]
+	} else if count := len(record.Sources); count > 0 {
+		result = record.Sources[count-1]
+	}
+	return result
+}
+
+func calculateValueRecordColumnWidths(records []ValueRecord) (int, int) {
+	nameWidth := 0
+	valueWidth := 0
+	for _, record := range records {
+		if len(record.Name) > nameWidth {
+			nameWidth = len(record.Name)
+		}
+		for _, value := range record.Values {
+			if len(value) > valueWidth {
+				valueWidth = len(value)
+			}
+		}
+	}
+	return nameWidth, valueWidth
+}
@@ -7,30 +7,52 @@ import (
 	"github.com/echsylon/go-args/internal/model"
 )
 
-func GetMainHelpSection(name string, description string, options *[]model.Option, arguments *[]model.Argument) string {
+func GetMainHelpSection(name string, description string, options *[]model.Option, arguments *[]model.Argument, commands *[]model.Command) string {
 	var stringBuilder strings.Builder
 	stringBuilder.WriteString("Usage: ")
 	stringBuilder.WriteString(name)
 
 	if options != nil {
-		optionsCount := len(*options)
+		var required []string
+		optionsCount := 0
+		for _, option := range *options {
+			if option.IsRequired() {
+				required = append(required, getOptionUsageName(option))
+			} else {
+				optionsCount++
+			}
+		}
+
 		if optionsCount > 1 {
 			stringBuilder.WriteString(" [OPTIONS...]")
 		} else if optionsCount > 0 {
 			stringBuilder.WriteString(" [OPTION]")
 		}
+
+		for _, name := range required {
+			stringBuilder.WriteString(" ")
+			stringBuilder.WriteString(name)
+		}
 	}
 
 	if arguments != nil {
 		for _, argument := range *arguments {
-			stringBuilder.WriteString(" ")
-			stringBuilder.WriteString(argument.GetName())
+			text := argument.GetName()
 			if argument.ExpectsMultipleValues() {
-				stringBuilder.WriteString("...")
+				text += "..."
+			}
+			if argument.HasDefaultValues() {
+				text = "[" + text + "]"
 			}
+			stringBuilder.WriteString(" ")
+			stringBuilder.WriteString(text)
 		}
 	}
 
+	if commands != nil && len(*commands) > 0 {
+		stringBuilder.WriteString(" COMMAND")
+	}
+
 	if description != "" {
 		stringBuilder.WriteString("\n")
 		stringBuilder.WriteString(description)
@@ -52,11 +74,12 @@ func GetOptionsHelpSection(options *[]model.Option) string {
 			shortText := buildOptionShortNameColumn(shortName, shortColumnWidth)
 			stringBuilder.WriteString(shortText)
 
-			longName := option.GetLongName()
-			longText := buildOptionLongNameColumn(shortName, longName, longColumnWidth)
+			longName := getOptionLongNameText(option)
+			suffix := getOptionNameSuffix(option)
+			longText := buildOptionLongNameColumn(shortName, longName, suffix, longColumnWidth)
 			stringBuilder.WriteString(longText)
 
-			description := option.GetDescription()
+			description := buildDescriptionColumn(option.GetDescription(), getOptionNotes(option))
 			stringBuilder.WriteString("  " + description)
 		}
 	}
@@ -77,7 +100,7 @@ func GetArgumentsHelpSection(arguments *[]model.Argument) string {
 			text := buildArgumentNameColumn(name, columnWidth)
 			stringBuilder.WriteString(text)
 
-			description := argument.GetDescription()
+			description := buildDescriptionColumn(argument.GetDescription(), getArgumentNotes(argument))
 			stringBuilder.WriteString("  " + description)
 		}
 	}
@@ -85,6 +108,105 @@ func GetArgumentsHelpSection(arguments *[]model.Argument) string {
 	return stringBuilder.String()
 }
 
+func GetCommandsHelpSection(commands *[]model.Command) string {
+	var names []string
+	var descriptions []string
+	if commands != nil {
+		for _, command := range *commands {
+			names = append(names, strings.Join(append([]string{command.GetName()}, command.GetAliases()...), ", "))
+			descriptions = append(descriptions, command.GetDescription())
+		}
+	}
+	return buildNamedListSection("Commands:", names, descriptions)
+}
+
+func GetPluginsHelpSection(plugins *[]model.Plugin) string {
+	var names []string
+	var paths []string
+	if plugins != nil {
+		for _, plugin := range *plugins {
+			names = append(names, plugin.GetName())
+			paths = append(paths, plugin.GetPath())
+		}
+	}
+	return buildNamedListSection("Plugins:", names, paths)
+}
+
+func GetTopicsHelpSection(topics *[]model.Topic) string {
+	var names []string
+	var descriptions []string
+	if topics != nil {
+		for _, topic := range *topics {
+			names = append(names, topic.GetName())
+			descriptions = append(descriptions, topic.GetDescription())
+		}
+	}
+	return buildNamedListSection("Help topics:", names, descriptions)
+}
+
+func buildNamedListSection(title string, names []string, descriptions []string) string {
+	var stringBuilder strings.Builder
+	if len(names) > 0 {
+		columnWidth := calculateNameColumnWidth(names)
+		stringBuilder.WriteString(title)
+
+		for index, name := range names {
+			stringBuilder.WriteString("\n")
+
+			text := buildArgumentNameColumn(name, columnWidth)
+			stringBuilder.WriteString(text)
+			stringBuilder.WriteString("  " + descriptions[index])
+		}
+	}
+
+	return stringBuilder.String()
+}
+
+func getOptionUsageName(option model.Option) string {
+	result := "--" + option.GetLongName()
+	if option.GetLongName() == "" {
+		result = "-" + option.GetShortName()
+	}
+	return result
+}
+
+func getOptionNotes(option model.Option) []string {
+	var notes []string
+	if option.IsRequired() {
+		notes = append(notes, "required")
+	}
+	if option.HasDefaultValue() {
+		notes = append(notes, "default: "+option.GetDefaultValue())
+	}
+	if variable := option.GetEnvironmentVariable(); variable != "" {
+		notes = append(notes, "env: "+variable)
+	}
+	return notes
+}
+
+func getArgumentNotes(argument model.Argument) []string {
+	var notes []string
+	if argument.HasDefaultValues() {
+		notes = append(notes, "default: "+strings.Join(argument.GetDefaultValues(), ", "))
+	}
+	if variable := argument.GetEnvironmentVariable(); variable != "" {
+		notes = append(notes, "env: "+variable)
+	}
+	return notes
+}
+
+func buildDescriptionColumn(description string, notes []string) string {
+	result := description
+	if len(notes) > 0 {
+		text := "(" + strings.Join(notes, "; ") + ")"
+		if result != "" {
+			result += " "
+		}
+		result += text
+	}
+	return result
+}
+
 func calculateOptionNamesColumnWidth(options *[]model.Option) (int, int) {
 	shortWidth := 0
 	longWidth := 0
@@ -95,7 +217,7 @@ func calculateOptionNamesColumnWidth(options *[]model.Option) (int, int) {
 				shortWidth = shortNameWidth
 			}
 
-			longNameWidth := len(option.GetLongName())
+			longNameWidth := len(getOptionLongNameText(option)) + len(getOptionNameSuffix(option))
 			if longNameWidth > longWidth {
 				longWidth = longNameWidth
 			}
@@ -117,7 +239,23 @@ func buildOptionShortNameColumn(shortName string, columnWidth int) string {
 	return result
 }
 
-func buildOptionLongNameColumn(shortName string, longName string, columnWidth int) string {
+func getOptionLongNameText(option model.Option) string {
+	result := option.GetLongName()
+	if result != "" && option.IsNegatable() {
+		result = "[no-]" + result
+	}
+	return result
+}
+
+func getOptionNameSuffix(option model.Option) string {
+	result := ""
+	if option.IsRepeatable() && !option.IsNegatable() {
+		result = "..."
+	}
+	return result
+}
+
+func buildOptionLongNameColumn(shortName string, longName string, suffix string, columnWidth int) string {
 	result := ""
 	if columnWidth > 0 {
 		text := ""
@@ -126,6 +264,7 @@ func buildOptionLongNameColumn(shortName string, longName string, columnWidth in
 		} else if longName != "" && shortName != "" {
 			text = ", --" + longName
 		}
+		text += suffix
 		width := columnWidth + 4 // prefix
 		result = fmt.Sprintf("%*s", -width, text)
 	}
@@ -144,6 +283,17 @@ func calculateArgumentNameColumnWidth(arguments *[]model.Argument) int {
 	}
 	return widestWidth
 }
+
+func calculateNameColumnWidth(names []string) int {
+	widestWidth := 0
+	for _, name := range names {
+		if len(name) > widestWidth {
+			widestWidth = len(name)
+		}
+	}
+	return widestWidth
+}
+
 func buildArgumentNameColumn(name string, columnWidth int) string {
 	result := ""
 	if columnWidth > 0 {
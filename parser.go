@@ -0,0 +1,1015 @@
+package args
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/echsylon/go-args/internal/data"
+	"github.com/echsylon/go-args/internal/domain"
+	"github.com/echsylon/go-args/internal/util"
+)
+
+// Parser holds a set of option and argument definitions together with the
+// values parsed for them. Each parser is fully independent of any other
+// parser, which allows an application to parse several unrelated sets of
+// input in the same process.
+//
+// The package level functions operate on a default parser, named after the
+// running executable.
+//
+// A parser may hold commands, created with AddCommand, which are parsers of
+// their own. See AddCommand for details.
+type Parser struct {
+	state         domain.StateMachine
+	root          *Parser
+	parent        *Parser
+	commands      []*Parser
+	handler       Handler
+	stdout        io.Writer
+	stderr        io.Writer
+	exit          func(code int)
+	usageExitCode int
+}
+
+// DefaultUsageExitCode is the exit status Parse uses by default when the
+// caller provided input fails validation.
+const DefaultUsageExitCode = 2
+
+// NewParser creates a new, empty parser. The name and description are only
+// shown in the help output.
+func NewParser(name string, description string) *Parser {
+	parser := &Parser{
+		state:         domain.NewStateMachine(name, description, data.NewRepository()),
+		stdout:        os.Stdout,
+		stderr:        os.Stderr,
+		exit:          os.Exit,
+		usageExitCode: DefaultUsageExitCode,
+	}
+	parser.root = parser
+	return parser
+}
+
+// SetApplicationDescription takes a human readable description of the app.
+// This text is only shown in the help output.
+func (parser *Parser) SetApplicationDescription(text string) {
+	parser.state.SetDescription(text)
+}
+
+// SetOutput replaces the writers Parse prints to. A requested help text is
+// written to stdout, while validation failures are written to stderr. Nil
+// writers are ignored.
+func (parser *Parser) SetOutput(stdout io.Writer, stderr io.Writer) {
+	if stdout != nil {
+		parser.root.stdout = stdout
+	}
+	if stderr != nil {
+		parser.root.stderr = stderr
+	}
+}
+
+// SetExitFunction replaces the function Parse calls to terminate the
+// application, os.Exit by default. This is mostly useful in tests. A nil
+// function is ignored.
+func (parser *Parser) SetExitFunction(exit func(code int)) {
+	if exit != nil {
+		parser.root.exit = exit
+	}
+}
+
+// SetUsageExitCode sets the exit status Parse terminates the application
+// with when the caller provided input fails validation. The default status
+// is DefaultUsageExitCode.
+func (parser *Parser) SetUsageExitCode(code int) {
+	parser.root.usageExitCode = code
+}
+
+// AddCommand adds a command to the parser and returns it. A command is a
+// parser of its own, with its own options and arguments, allowing `tool
+// build|deploy|status` style applications. Commands may have commands of
+// their own.
+//
+// The first input token that isn't an option or an option value selects the
+// command, and all following input is parsed against it. A token naming a
+// command is never taken as an option value, so `tool --verbose build`
+// selects "build" even if `--verbose` accepts a value. Options defined on
+// the parser are inherited by its commands, so global options like
+// `--verbose` may be given both before and after the command name. Options
+// defined on a command are only accepted after the command name. If the
+// caller doesn't pass a command, or passes an unknown one, the parsing fails
+// with a MissingCommand or UnknownCommand error. GetCommand tells which
+// command was selected, and the values are read from the command parser.
+//
+// Parsing, output and policy settings are shared by the whole parser tree,
+// regardless of which parser they are called on. Help texts list the
+// commands in a "Commands:" section and describe the selected command.
+//
+// Aliases, if given, select the command just like its name, e.g. "rm" for
+// "remove". They are shown next to the name in the help text. See also
+// SetCommandAbbreviationsEnabled.
+//
+// A parser can't have both commands and arguments. If arguments are already
+// defined, if the name or any alias is invalid or if the command is already
+// defined, the library will panic runtime.
+func (parser *Parser) AddCommand(name string, description string, aliases ...string) *Parser {
+	state, err := parser.state.AddCommand(name, description)
+	for index := 0; err == nil && index < len(aliases); index++ {
+		err = state.AddAlias(aliases[index])
+	}
+	if err != nil {
+		panic(err)
+	}
+	command := &Parser{state: state, root: parser.root, parent: parser}
+	parser.commands = append(parser.commands, command)
+	return command
+}
+
+// SetHandler registers the function Run and Execute call when this parser
+// is the selected command, replacing any previous handler. A handler set on
+// a parent parser also handles selected commands without handlers of their
+// own, which makes a handler on the root parser serve applications without
+// commands.
+func (parser *Parser) SetHandler(handler Handler) {
+	parser.handler = handler
+}
+
+// AddHelpCommand adds a command that prints help texts, usually named
+// "help". Without further input it prints the help text of the parser it was
+// added to, e.g. `tool help`. Given a command path it prints the help text of
+// that command, e.g. `tool help remote add`, just like `tool remote add
+// --help` would, if a help option is defined. Given the name of a help topic,
+// see AddHelpTopic, it prints the topic text, e.g. `tool help patterns`.
+// Given its own name it describes itself, e.g. `tool help help`.
+//
+// Unknown commands or topics fail the parsing with an UnknownCommand error.
+// Otherwise a HelpRequested error is reported, just like for help options.
+//
+// If the name is invalid or already defined, the library will panic runtime.
+func (parser *Parser) AddHelpCommand(name string, description string) {
+	err := parser.state.AddHelpCommand(name, description)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddHelpTopic registers a free-form help text under a name, for the help
+// command to print, e.g. `tool help patterns`. The help text of the parser
+// lists the topics with their descriptions in a "Help topics:" section.
+// Topics registered on a command are reached through its path, e.g. `tool
+// help deploy targets`.
+//
+// Topics are only reachable through a help command, see AddHelpCommand. If
+// the name is invalid or is already used by a command or topic, the library
+// will panic runtime.
+func (parser *Parser) AddHelpTopic(name string, description string, text string) {
+	err := parser.state.AddHelpTopic(name, description, text)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetPlugin returns the path of the plugin executable selected by the last
+// parsed input, see SetPluginsEnabled, and the input tokens following the
+// plugin name, e.g. "/usr/local/bin/tool-foo" and `--bar` for `tool foo
+// --bar`. The path is empty if no plugin was selected.
+func (parser *Parser) GetPlugin() (string, []string) {
+	var path = ""
+	plugin, input := parser.root.state.GetSelectedPlugin()
+	if plugin != nil {
+		path = plugin.GetPath()
+	}
+	return path, input
+}
+
+// GetCommand returns the name of the command the caller selected among the
+// commands of this parser, or an empty string if none was selected.
+func (parser *Parser) GetCommand() string {
+	var result = ""
+	if command := parser.state.GetSelectedCommand(); command != nil {
+		result = command.GetName()
+	}
+	return result
+}
+
+// SetConflictPolicy decides what happens when the caller passes conflicting
+// values for a boolean option, e.g. `--color --no-color`. With LastWins,
+// which is the default, the last value is used. With RejectConflicts the
+// parsing fails with a ConflictingValues error.
+func (parser *Parser) SetConflictPolicy(policy ConflictPolicy) {
+	parser.state.SetConflictPolicy(policy)
+}
+
+// SetEnvironmentPrefix sets a prefix that is prepended to the names of all
+// environment variables bound to options and arguments, e.g. "APP_" makes a
+// variable named "PORT" read from "APP_PORT". The prefix applies to both
+// existing and future bindings.
+func (parser *Parser) SetEnvironmentPrefix(prefix string) {
+	parser.state.SetEnvironmentPrefix(prefix)
+}
+
+// SetConfigFile enables reading option and argument values from a config
+// file. The file format is decided by the file extension:
+//
+//   - JSON (any extension but the below): a flat object where each key is an
+//     option long name or an argument name, and each value is a string,
+//     number or boolean, or an array of such values for repeatable options
+//     and arguments, e.g. `{"port": 8080, "include": ["vendor", "lib"]}`.
+//
+//   - INI (".ini"): `key = value` lines. A key given several times collects
+//     all its values.
+//
+//   - TOML (".toml"): a subset supporting tables, bare, quoted and dotted
+//     keys, single line strings, integers, floats, booleans and arrays.
+//
+// INI sections and TOML tables prefix the keys they hold, so `port` in a
+// `[server]` section sets the `server.port` option.
+//
+// Options are keyed by their long name, or by their short name if they have
+// no long name. A short name key of an option with a long name is unknown.
+//
+// If optionName is given, it must name a defined option taking a value. When
+// the caller passes that option (or it's given through its environment
+// variable) its value is used as the file path, and the file must exist. Else
+// the defaultPath is used, if given, and a missing file is silently ignored.
+//
+// Values from the config file have lower precedence than both command line
+// input and environment variables, but higher than declared default values.
+// Each value must match the pattern of its definition. Unknown keys, invalid
+// values and malformed files fail the parsing with an error naming the file
+// and line, e.g. "app.json:3: invalid value for port: http". With commands,
+// the file is read against the selected command, and keys defined only on
+// other commands are ignored, so one file may serve all commands. Keys
+// prefixed with a command name, e.g. "deploy.target" or `target` in a
+// `[deploy]` INI section or TOML table, are looked up in the definitions of
+// that command, and only applied when that command is selected.
+//
+// If the option isn't defined or doesn't take a value, the library will
+// panic runtime.
+func (parser *Parser) SetConfigFile(optionName string, defaultPath string) {
+	err := parser.state.SetConfigFile(optionName, defaultPath)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SetResponseFilesEnabled enables or disables response file expansion, which
+// is disabled by default. When enabled, each input token starting with `@`
+// is replaced by the tokens read from the file it names, e.g. `@args.txt`.
+// This allows callers to work around operating system limits on the command
+// line length.
+//
+// The file content is split into tokens the way a POSIX shell would: tokens
+// are separated by whitespace, single quotes preserve their content
+// literally, double quotes allow escaping `"` and `\`, a backslash outside
+// quotes escapes the next character and a `#` starting a token comments out
+// the rest of the line. Response files may include other response files, but
+// not themselves, directly or indirectly. Relative paths are resolved from
+// the working directory.
+//
+// No expansion happens after the `--` end-of-options token, which allows
+// passing literal values starting with `@`. A missing or malformed response
+// file fails the parsing with an InvalidResponseFile error naming the file
+// and line, if any. Errors for tokens read from a response file name the
+// file and line of the token as well.
+func (parser *Parser) SetResponseFilesEnabled(enabled bool) {
+	parser.state.SetResponseFilesEnabled(enabled)
+}
+
+// SetCommandAbbreviationsEnabled allows the caller to select a command by any
+// unambiguous prefix of its name or aliases, e.g. `tool dep` for `tool
+// deploy`. An exact name or alias always wins. A prefix of more than one
+// command fails the parsing with an AmbiguousCommand error, e.g.
+// "ambiguous command: dep (deploy, deps)". Abbreviations are disabled by
+// default, since adding a command may break previously unique prefixes.
+func (parser *Parser) SetCommandAbbreviationsEnabled(enabled bool) {
+	parser.state.SetCommandAbbreviationsEnabled(enabled)
+}
+
+// SetOptionAbbreviationsEnabled allows the caller to abbreviate long option
+// names to any unambiguous prefix, the way GNU getopt_long does, e.g.
+// `--verb` or `--verb=2` for `--verbose`. Negated boolean options can be
+// abbreviated as well, e.g. `--no-col` for `--no-color`. An exact name always
+// wins. A prefix of more than one name fails the parsing with an
+// AmbiguousOption error listing the candidates, e.g. "ambiguous option:
+// --ver (--verbose, --version)". Abbreviations are disabled by default.
+func (parser *Parser) SetOptionAbbreviationsEnabled(enabled bool) {
+	parser.state.SetOptionAbbreviationsEnabled(enabled)
+}
+
+// SetPluginsEnabled turns on git style external commands. When enabled, a
+// word in command position that doesn't name a defined command is looked up
+// as an executable named after the command path, e.g. `tool foo --bar` looks
+// for "tool-foo" and `tool remote foo` for "tool-remote-foo". If found, the
+// parsing stops there and Run and Execute run the executable with the
+// remaining input tokens, e.g. `--bar`, instead of calling a handler. Its
+// exit status becomes the exit status of the application.
+//
+// Plugins are only looked up for parsers without arguments, and defined
+// commands always take precedence. Options given before the plugin name are
+// parsed as usual. The help text lists the plugins found in a "Plugins:"
+// section. The lookup directories are set with SetPluginDirectories.
+//
+// Parse, TryParse and ParseArgs don't run the plugin. Applications using them
+// instead of Run or Execute must check GetPlugin after parsing.
+func (parser *Parser) SetPluginsEnabled(enabled bool) {
+	parser.state.SetPluginsEnabled(enabled)
+}
+
+// SetPluginDirectories sets the directories to look for plugin executables
+// in, in order, see SetPluginsEnabled. By default the directories of the
+// PATH environment variable are used.
+func (parser *Parser) SetPluginDirectories(directories ...string) {
+	parser.state.SetPluginDirectories(directories)
+}
+
+// DefineOption allows the developer to define a simple optional command line
+// argument the caller can pass to the application. Only defined options will
+// be accepted during the parsing phase.
+//
+// If the name is a single character it will serve as the shortName, else as
+// the longName. The name is a mandatory field.
+//
+// The description, if given, is only shown in the help output.
+//
+// See DefineOptionStrict for more details.
+func (parser *Parser) DefineOption(name string, description string) {
+	if len(name) > 1 {
+		parser.DefineOptionStrict("", name, description, "")
+	} else {
+		parser.DefineOptionStrict(name, "", description, "")
+	}
+}
+
+// DefineOptionStrict allows the developer to define an optional command
+// line argument the caller can pass to the application.
+//
+// At least a shortName or a longName must be given. Defining both is nice
+// but not functionally required. The shortName must be a single alphabetic
+// character matching the `^[a-zA-Z]{1}$` regular expression, while the
+// longName must be at least two alphabetic+ characters, matching the
+// `^[a-zA-Z-._]{2,}$` regular expression.
+//
+// The description, if given, is only shown in the help output.
+//
+// If a pattern regular expression is given, then any caller provided value
+// for this option must match it for the value to be assigned to the option.
+// Empty string patterns are allowed and will behave as "accept everything".
+// The library will validate the pattern regular expression. If the validation
+// fails the library will panic runtime.
+//
+// The caller can pass a value either as the next input token (`--name value`
+// or `-n value`) or attached to the option name (`--name=value` or
+// `-nvalue`). Attached values must match the option pattern, else parsing
+// fails.
+//
+// Short options can be clustered, so `-vqx` is the same as `-v -q -x`. The
+// last option in a cluster may take the next input token as its value, so
+// `-vo out.txt` is the same as `-v -o out.txt`. A cluster is only expanded
+// as long as the remaining characters are all defined short names, else
+// they are taken as an attached value, so `-vout.txt` assigns "out.txt" to
+// the `-v` option.
+//
+// If a caller passes the defined (short or long) option name alone, without
+// any corresponding value, the library will treat it as a boolean true flag
+// and return "true" for it's value.
+//
+// If the caller provides multiple instances of the same option, the library
+// will print a help text and exit the application gracefully.
+//
+// If the caller passes a detached value that doesn't match the given option
+// pattern, then the library will try to match the value for any argument
+// instead. If there is a defined argument that accepts the value it will be
+// assigned to that argument, otherwise the library will print a help text and
+// exit the application gracefully.
+func (parser *Parser) DefineOptionStrict(shortName string, longName string, description string, pattern string) {
+	err := parser.state.DefineOption(shortName, longName, description, pattern)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DefineOptionRepeatable allows the developer to define an option the caller
+// can pass multiple times, e.g. `-I include -I vendor/include`. All values
+// are collected in the order they were given and can be retrieved with
+// GetOptionValues, while GetOptionValue returns the last one.
+//
+// The names, the description and the pattern follow the same rules as for
+// DefineOptionStrict.
+//
+// The minCount and maxCount limit how many times the caller may pass the
+// option. The minCount must be greater than or equal to 0 and the maxCount
+// must be greater than or equal to both minCount and 1. If the library fails
+// to validate the range it will panic runtime.
+//
+// If the caller passes the option fewer than minCount or more than maxCount
+// times, the library will print a help text and exit the application
+// gracefully.
+func (parser *Parser) DefineOptionRepeatable(shortName string, longName string, description string, minCount int, maxCount int, pattern string) {
+	err := parser.state.DefineRepeatableOption(shortName, longName, description, minCount, maxCount, pattern)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DefineOptionCounter allows the developer to define an option that counts
+// how many times the caller passes it, e.g. `-v`, `-vv` or `-v -v -v` for
+// increasing verbosity levels. The count can be retrieved with
+// GetOptionCount. A counter option never takes a value.
+//
+// The names and the description follow the same rules as for
+// DefineOptionStrict. The help text will indicate that the option may be
+// repeated.
+func (parser *Parser) DefineOptionCounter(shortName string, longName string, description string) {
+	err := parser.state.DefineCounterOption(shortName, longName, description)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DefineOptionBool allows the developer to define a boolean option that can
+// be explicitly turned both on and off. Passing the option alone, e.g.
+// `--color`, yields "true", while passing its negated long name, e.g.
+// `--no-color`, yields "false". An attached value, e.g. `--color=false`, is
+// also accepted as long as it's either "true" or "false". A boolean option
+// never takes a detached value.
+//
+// The names and the description follow the same rules as for
+// DefineOptionStrict. Only options with a long name can be negated. The help
+// text will show the option as `--[no-]color`.
+//
+// If the caller passes the option multiple times, the last value wins by
+// default. See SetConflictPolicy for how to reject conflicting values
+// instead.
+func (parser *Parser) DefineOptionBool(shortName string, longName string, description string) {
+	err := parser.state.DefineBooleanOption(shortName, longName, description)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DefineOptionHelp allows the devleoper to define a graceful help trigger
+// option.
+//
+// At least a shortName or a longName must be given. Defining both is nice
+// but not functionally required. The shortName must be a single alphabetic
+// character matching the `^[a-zA-Z]{1}$` regular expression, while the
+// longName must be at least two alphabetic+ characters, matching the
+// `^[a-zA-Z-._]{2,}$` regular expression.
+//
+// The description, if given, is only shown in the help output.
+//
+// If a caller passes this option, the library will immediately abort parsing
+// and print the help text - without any error message (!) - and exit the
+// application gracefully.
+func (parser *Parser) DefineOptionHelp(shortName string, longName string, description string) {
+	err := parser.state.DefineHelpOption(shortName, longName, description)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SetOptionDefaultValue declares the value a defined option has when the
+// caller doesn't pass it. The getters return the default value instead of
+// their fallback, and the help text shows it next to the option description.
+//
+// The value must match the option pattern. If the option isn't defined, if
+// it doesn't take a value (e.g. a help or counter option) or if the value
+// doesn't match the pattern, the library will panic runtime.
+func (parser *Parser) SetOptionDefaultValue(name string, value string) {
+	err := parser.state.SetOptionDefaultValue(name, value)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SetOptionRequired marks a defined option as required. If the caller
+// doesn't pass a required option, and there is no other source for its value
+// (e.g. a declared default value), the library will print a "missing input"
+// error and the help text and exit the application. Required options are
+// listed by name in the usage line of the help text rather than under the
+// generic options notation.
+//
+// If the option isn't defined or if it's a help option, the library will
+// panic runtime.
+func (parser *Parser) SetOptionRequired(name string) {
+	err := parser.state.SetOptionRequired(name)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SetOptionEnvironmentVariable binds a defined option to an environment
+// variable. If the caller doesn't pass the option on the command line, but
+// the variable is set, the variable value is used instead. Command line input
+// always wins over the environment, which in turn wins over a declared
+// default value. The help text shows the variable next to the option
+// description.
+//
+// The environment value must match the option pattern, or the parsing fails
+// with an InvalidValue error naming the variable.
+//
+// If the option isn't defined, if it doesn't take a value (e.g. a help or
+// counter option) or if the variable name isn't a valid environment variable
+// name, the library will panic runtime.
+func (parser *Parser) SetOptionEnvironmentVariable(name string, variable string) {
+	err := parser.state.SetOptionEnvironmentVariable(name, variable)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DefineArgument allows the developer to define a mandatory argument the
+// caller must pass to the application. By default the defined argument will
+// accept exactly one value of any shape and size.
+//
+// See DefineArgumentStrict for more details.
+func (parser *Parser) DefineArgument(name string, description string) {
+	parser.DefineArgumentStrict(name, description, 1, 1, "")
+}
+
+// DefineArgumentStrict allows the developer to define more granular
+// mandatory arguments the caller must pass to the application.
+//
+// If minCount and maxCount is given the number of caller provided values will
+// be validated to be in that (inclusive) range. The minCount must be greater
+// than or equal to 1 and the maxCount must be greater than or equal to minCount.
+//
+// If a pattern is given, then the caller provided input argument value will be
+// matched against it. This allows the caller to mix the order of input values.
+// The values are assigned to the argument definitions as a whole, once all
+// input is read: each value goes to an argument whose pattern matches it, and
+// every argument gets between minCount and maxCount values, if at all
+// possible. A multi-value argument therefore never swallows a value a later
+// argument needs. When several assignments are valid, each value, in input
+// order, goes to the first argument definition that can take it. It is the
+// developers responsibility to define non-overlapping patterns (if this is
+// important) and to provide sufficient documentation in the argument
+// descriptions for the caller to make an educated call statement.
+//
+// If there are more values than the arguments can take, the parsing fails
+// with a TooManyValues error for the first value that can't be assigned,
+// naming the arguments it matches, e.g. "too many values for FILES, TIMEOUT:
+// file3.txt". If a minCount can't be met, the parsing fails with a
+// MissingArgument error naming the argument.
+//
+// If a pattern is given, it will be validated, causing the library to panic
+// runtime if it's invalid.
+//
+// Input starting with a dash is treated as an option, except for negative
+// numbers like `-5` or `-2.5`, which are treated as values. If the caller
+// needs to pass any other value starting with a dash, it can be given after
+// the `--` end-of-options token. All input after that token is matched
+// against arguments only.
+//
+// If the caller fails to pass the constrained number of matching arguments,
+// the library will print a help text and exit the application gracefully.
+//
+// If the caller provides an argument value that doesn't match any defined
+// arguments, the library will print a help text and exit the application
+// gracefully.
+func (parser *Parser) DefineArgumentStrict(name string, description string, minCount int, maxCount int, pattern string) {
+	err := parser.state.DefineArgument(name, description, minCount, maxCount, pattern)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SetArgumentDefaultValues declares the values a defined argument has when
+// the caller doesn't pass any values for it. This effectively makes the
+// argument optional. The getters return the default values, and the help
+// text shows them next to the argument description.
+//
+// Each value must match the argument pattern and the number of values must
+// be within the argument count range. If the argument isn't defined or if
+// the values don't meet the constraints, the library will panic runtime.
+func (parser *Parser) SetArgumentDefaultValues(name string, values ...string) {
+	err := parser.state.SetArgumentDefaultValues(name, values)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SetArgumentEnvironmentVariable binds a defined argument to an environment
+// variable. If the caller doesn't pass any values for the argument, but the
+// variable is set, the variable value is used as the single argument value.
+// Command line input always wins over the environment, which in turn wins
+// over declared default values. The help text shows the variable next to the
+// argument description.
+//
+// The environment value must match the argument pattern, or the parsing
+// fails with an InvalidValue error naming the variable.
+//
+// If the argument isn't defined or if the variable name isn't a valid
+// environment variable name, the library will panic runtime.
+func (parser *Parser) SetArgumentEnvironmentVariable(name string, variable string) {
+	err := parser.state.SetArgumentEnvironmentVariable(name, variable)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Parse operates on the user provided command line arguments and matches them
+// against the developer defined option and argument configurations. The parse
+// function will validate the input and print an error message followed by the
+// help text to stderr and exit with the usage exit code (2 by default) if:
+//
+//   - An unknown option is parsed.
+//
+//   - A value doesn't match any option or argument patterns.
+//
+//   - An argument value would violate the defined argument value count limits.
+//
+//   - After all input is parsed, there are defined mandatory arguments that
+//     hasn't received the minimum number of input values.
+//
+// If the caller passes a help option, the help text is printed to stdout and
+// the application exits with status 0.
+func (parser *Parser) Parse() {
+	if err := parser.state.Parse(); err != nil {
+		parser.exitWithHelpMessage(err)
+	}
+}
+
+// TryParse operates on the user provided command line arguments just like
+// Parse, but rather than printing the help text and exiting, any validation
+// failure is returned to the caller as a *ParseError. This leaves it to the
+// application to decide how to report the failure and how to exit.
+//
+// A caller provided help option is reported as a *ParseError of the
+// HelpRequested kind.
+func (parser *Parser) TryParse() error {
+	return parser.ParseArgs(os.Args[1:])
+}
+
+// Execute parses the command line arguments, runs the handler of the selected
+// command and terminates the application with the resulting exit status. See
+// Run for details.
+func (parser *Parser) Execute() {
+	parser.root.exit(parser.Run(os.Args[1:]))
+}
+
+// Run parses the given input tokens, selects the command and runs its handler,
+// returning the exit status the application should terminate with, e.g.
+// `os.Exit(parser.Run(os.Args[1:]))`. This replaces the switch statement on
+// the selected command otherwise needed after Parse.
+//
+// If the input fails validation, or the caller requests help, the help text
+// is printed just like with Parse and the usage exit code (or 0 for help) is
+// returned. A handler returning a *ParseError is treated the same way. Any
+// other error returned by a handler is printed to stderr, prefixed by the
+// application name. The exit status is then taken from the error if it
+// implements ExitCoder (see WithExitCode), or else DefaultErrorExitCode. A
+// handler returning nil gives exit status 0.
+//
+// If neither the selected command nor any of its parents has a handler, the
+// library will panic runtime.
+func (parser *Parser) Run(input []string) int {
+	var root = parser.root
+	var code = 0
+	if err := root.state.ParseArgs(input); err != nil {
+		code = root.printHelpMessage(err)
+	} else if plugin, pluginInput := root.state.GetSelectedPlugin(); plugin != nil {
+		code = root.runPlugin(plugin.GetPath(), pluginInput)
+	} else if command, handler := root.findHandler(); handler == nil {
+		panic(fmt.Errorf("no handler for command: %s", command.state.GetPath()))
+	} else if err := handler(newContext(command)); err != nil {
+		code = root.printHandlerError(err)
+	}
+	return code
+}
+
+// ParseArgs operates on the given input tokens instead of the command line
+// arguments. The input is validated the same way as in Parse, but rather than
+// printing the help text and exiting, any validation failure is returned to
+// the caller as a *ParseError. This allows parsing input that arrives from
+// other sources than the command line, e.g. a configuration line or a REPL.
+//
+// Any values from a previous parse are purged before the input is parsed.
+func (parser *Parser) ParseArgs(input []string) error {
+	return parser.state.ParseArgs(input)
+}
+
+// GetOptionValue returns the parsed value for a defined option as a string.
+// If there is no parsed value for the option, the declared default value is
+// returned, or if there is none, the fallback is returned instead.
+func (parser *Parser) GetOptionValue(name string, fallback string) string {
+	result := parser.state.GetOptionValue(name)
+	if result == "" {
+		result = fallback
+	}
+	return result
+}
+
+// GetOptionIntValue returns the parsed value for a defined option as a 64 bit
+// integer. If there is no parsed value for the option, the fallback is
+// returned instead.
+func (parser *Parser) GetOptionIntValue(name string, fallback int64) int64 {
+	value := parser.state.GetOptionValue(name)
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		result = fallback
+	}
+	return result
+}
+
+// GetOptionFloatValue returns the parsed value for a defined option as a
+// 64 bit floating point number. If there is no parsed value for the option,
+// the fallback is returned instead.
+func (parser *Parser) GetOptionFloatValue(name string, fallback float64) float64 {
+	value := parser.state.GetOptionValue(name)
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		result = fallback
+	}
+	return result
+}
+
+// GetOptionBoolValue returns the parsed value for a defined option as a
+// boolean. If there is no parsed value for the option, the fallback is
+// returned instead.
+func (parser *Parser) GetOptionBoolValue(name string, fallback bool) bool {
+	value := parser.state.GetOptionValue(name)
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		result = fallback
+	}
+	return result
+}
+
+// GetOptionValues returns all parsed values for a defined option in the order
+// they were given. This is mostly useful for options defined with
+// DefineOptionRepeatable.
+func (parser *Parser) GetOptionValues(name string) []string {
+	return parser.state.GetOptionValues(name)
+}
+
+// GetOptionCount returns the number of times the caller passed a defined
+// option. This is mostly useful for options defined with
+// DefineOptionCounter. Undefined options have a count of zero.
+func (parser *Parser) GetOptionCount(name string) int {
+	return parser.state.GetOptionCount(name)
+}
+
+// GetArgumentValues returns all parsed mandatory values that matched the
+// defined argument.
+func (parser *Parser) GetArgumentValues(name string) []string {
+	return parser.state.GetArgumentValues(name)
+}
+
+// GetArgumentIntValues returns all parsed mandatory values that matched the
+// defined argument and can be cast into a 64 bit integer. Values that can not
+// be cast into a 64 bit integer are simply omitted from the result.
+func (parser *Parser) GetArgumentIntValues(name string) []int64 {
+	values := parser.state.GetArgumentValues(name)
+	result := []int64{}
+	for _, value := range values {
+		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+			result = append(result, number)
+		}
+	}
+	return result
+}
+
+// GetArgumentFloatValues returns all parsed mandatory values that matched the
+// defined argument and can be cast into a 64 bit floating point number. Values
+// that can not be cast into a 64 bit floating point number are simply omitted
+// from the result.
+func (parser *Parser) GetArgumentFloatValues(name string) []float64 {
+	values := parser.state.GetArgumentValues(name)
+	result := []float64{}
+	for _, value := range values {
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			result = append(result, number)
+		}
+	}
+	return result
+}
+
+// GetArgumentBoolValues returns all parsed mandatory values that matched the
+// defined argument and can be cast into a boolean value. Values that can not
+// be cast into a boolean value are simply omitted from the result.
+func (parser *Parser) GetArgumentBoolValues(name string) []bool {
+	values := parser.state.GetArgumentValues(name)
+	result := []bool{}
+	for _, value := range values {
+		if state, err := strconv.ParseBool(value); err == nil {
+			result = append(result, state)
+		}
+	}
+	return result
+}
+
+// GetOptionSource returns where the effective value of a defined option came
+// from: the command line (with the index of the option token in the parsed
+// input), an environment variable, a config file and line, or the declared
+// default value. If the option wasn't given at all, a Source of kind NotGiven
+// is returned.
+func (parser *Parser) GetOptionSource(name string) Source {
+	var result = Source{Kind: NotGiven, Index: -1}
+	if sources := parser.state.GetOptionSources(name); len(sources) > 0 {
+		result = sources[len(sources)-1]
+	}
+	return result
+}
+
+// GetOptionSources returns the source of each occurrence of a defined option
+// in the order they were given. For options taking values, the sources line
+// up with the values returned by GetOptionValues.
+func (parser *Parser) GetOptionSources(name string) []Source {
+	return parser.state.GetOptionSources(name)
+}
+
+// GetArgumentSources returns the source of each value of a defined argument.
+// The sources line up with the values returned by GetArgumentValues.
+func (parser *Parser) GetArgumentSources(name string) []Source {
+	return parser.state.GetArgumentSources(name)
+}
+
+// DumpValues returns a human readable table of all defined options and
+// arguments, their effective values and where each value came from, e.g.
+//
+//	--timeout  30      (config file app.toml:4)
+//	--include  vendor  (command line, index 1)
+//	           lib     (environment variable APP_INCLUDE)
+//	FILE       a.txt   (command line, index 3)
+//
+// It's meant as a debugging aid and its format may change.
+func (parser *Parser) DumpValues() string {
+	return buildValuesDump(getSelectedState(parser.state))
+}
+
+// Reset will delete all previously configured options, arguments, commands
+// and handlers and purge any corresponding parsed values.
+func (parser *Parser) Reset() {
+	parser.state.Reset()
+	parser.commands = nil
+	parser.handler = nil
+}
+
+func (parser *Parser) exitWithHelpMessage(err error) {
+	parser.root.exit(parser.printHelpMessage(err))
+}
+
+func (parser *Parser) printHelpMessage(err error) int {
+	var root = parser.root
+	var output = root.stderr
+	var code = root.usageExitCode
+	if parseError, isParseError := err.(*ParseError); isParseError && parseError.Kind == HelpRequested {
+		output = root.stdout
+		code = 0
+	}
+
+	fmt.Fprintln(output, buildHelpMessage(err, getSelectedState(root.state)))
+	return code
+}
+
+func (parser *Parser) printHandlerError(err error) int {
+	var root = parser.root
+	var code = getExitCode(err)
+	if _, isParseError := err.(*ParseError); isParseError {
+		code = root.printHelpMessage(err)
+	} else if message := err.Error(); message != "" {
+		fmt.Fprintln(root.stderr, root.state.GetName()+": "+message)
+	}
+	return code
+}
+
+func (parser *Parser) runPlugin(path string, input []string) int {
+	var root = parser.root
+	var code = 0
+	var exitError *exec.ExitError
+	command := exec.Command(path, input...)
+	command.Stdin = os.Stdin
+	command.Stdout = root.stdout
+	command.Stderr = root.stderr
+	if err := command.Run(); errors.As(err, &exitError) && exitError.ExitCode() >= 0 {
+		code = exitError.ExitCode()
+	} else if err != nil {
+		code = root.printHandlerError(err)
+	}
+	return code
+}
+
+func (parser *Parser) findHandler() (*Parser, Handler) {
+	var command = parser
+	var handler = parser.handler
+	for name := command.GetCommand(); name != ""; name = command.GetCommand() {
+		command = command.findCommand(name)
+		if command.handler != nil {
+			handler = command.handler
+		}
+	}
+	return command, handler
+}
+
+func (parser *Parser) findCommand(name string) *Parser {
+	var result *Parser = nil
+	for _, command := range parser.commands {
+		if command.state.GetName() == name {
+			result = command
+			break
+		}
+	}
+	return result
+}
+
+func getSelectedState(state domain.StateMachine) domain.StateMachine {
+	var result = state
+	for result.GetSelectedCommand() != nil {
+		result = result.GetSelectedCommand()
+	}
+	return result
+}
+
+func buildValuesDump(state domain.StateMachine) string {
+	var records []util.ValueRecord
+	for _, option := range state.GetDefinedOptions() {
+		name := option.GetLongName()
+		displayName := "--" + name
+		if name == "" {
+			name = option.GetShortName()
+			displayName = "-" + name
+		}
+
+		record := util.ValueRecord{Name: displayName, Values: state.GetOptionValues(name), Sources: state.GetOptionSources(name)}
+		if count := state.GetOptionCount(name); option.IsCounter() && count > 0 {
+			record.Values = []string{strconv.Itoa(count)}
+			record.Sources = record.Sources[len(record.Sources)-1:]
+		}
+
+		if !option.IsHelpTrigger() {
+			records = append(records, record)
+		}
+	}
+
+	for _, argument := range state.GetDefinedArguments() {
+		name := argument.GetName()
+		records = append(records, util.ValueRecord{Name: name, Values: state.GetArgumentValues(name), Sources: state.GetArgumentSources(name)})
+	}
+
+	return util.GetValuesDump(records)
+}
+
+func buildHelpMessage(err error, state domain.StateMachine) string {
+	var stringBuilder strings.Builder
+	var name = state.GetPath()
+	var description = state.GetDescription()
+	var options = state.GetDefinedOptions()
+	var arguments = state.GetDefinedArguments()
+	var commands = state.GetDefinedCommands()
+	var topics = state.GetDefinedHelpTopics()
+	var plugins = state.GetDefinedPlugins()
+
+	if parseError, isParseError := err.(*ParseError); isParseError && parseError.Kind == HelpRequested {
+		if topic := state.GetHelpTopic(parseError.Token); topic != nil {
+			return topic.GetText()
+		}
+	}
+
+	var message = err.Error()
+	if message != "" {
+		stringBuilder.WriteString(message)
+		stringBuilder.WriteString("\n\n")
+	}
+
+	var mainSection = util.GetMainHelpSection(name, description, &options, &arguments, &commands)
+	if mainSection != "" {
+		stringBuilder.WriteString(mainSection)
+	}
+
+	var commandsSection = util.GetCommandsHelpSection(&commands)
+	if commandsSection != "" {
+		stringBuilder.WriteString("\n\n")
+		stringBuilder.WriteString(commandsSection)
+	}
+
+	var pluginsSection = util.GetPluginsHelpSection(&plugins)
+	if pluginsSection != "" {
+		stringBuilder.WriteString("\n\n")
+		stringBuilder.WriteString(pluginsSection)
+	}
+
+	var topicsSection = util.GetTopicsHelpSection(&topics)
+	if topicsSection != "" {
+		stringBuilder.WriteString("\n\n")
+		stringBuilder.WriteString(topicsSection)
+	}
+
+	var argumentsSection = util.GetArgumentsHelpSection(&arguments)
+	if argumentsSection != "" {
+		stringBuilder.WriteString("\n\n")
+		stringBuilder.WriteString(argumentsSection)
+	}
+
+	var optionsSection = util.GetOptionsHelpSection(&options)
+	if optionsSection != "" {
+		stringBuilder.WriteString("\n\n")
+		stringBuilder.WriteString(optionsSection)
+	}
+
+	return stringBuilder.String()
+}
@@ -0,0 +1,44 @@
+package args
+
+import "github.com/echsylon/go-args/internal/model"
+
+// Source describes where a parsed value came from.
+//
+// The Kind field tells which kind of source it was. For CommandLine sources
+// the Index field holds the position of the token in the parsed input (not
+// counting the application name), else it's -1. For EnvironmentVariable
+// sources the Name field holds the variable name and for ConfigFile and
+// ResponseFile sources it holds the file path, with the Line field holding
+// the line number.
+//
+// The String method describes the source in a human readable form, e.g.
+// "command line, index 2" or "config file app.json:3".
+type Source = model.Source
+
+// SourceKind classifies a Source.
+type SourceKind = model.SourceKind
+
+const (
+	// NotGiven is reported for options and arguments that didn't get a
+	// value from any source.
+	NotGiven = model.NotGiven
+
+	// CommandLine is reported for values given as input tokens.
+	CommandLine = model.CommandLine
+
+	// EnvironmentVariable is reported for values read from an environment
+	// variable bound with SetOptionEnvironmentVariable or
+	// SetArgumentEnvironmentVariable.
+	EnvironmentVariable = model.EnvironmentVariable
+
+	// ConfigFile is reported for values read from a config file enabled
+	// with SetConfigFile.
+	ConfigFile = model.ConfigFile
+
+	// DefaultValue is reported for declared default values.
+	DefaultValue = model.DefaultValue
+
+	// ResponseFile is reported for values read from a response file. The
+	// Index field holds the position of the `@file` input token.
+	ResponseFile = model.ResponseFile
+)
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/echsylon/go-args/internal/data"
+	"github.com/echsylon/go-args/internal/model"
 )
 
 func Test_WhenSavingAnOptionSuccessfully_ThenThatOptionCanBeRetrieved(t *testing.T) {
@@ -164,3 +165,58 @@ func Test_WhenClearingAllData_ThenAllOptionsAndArgumentsAreDeleted(t *testing.T)
 		t.Errorf("Expected <nil> and <nil>, but got <%v> and <%v>", option, argument)
 	}
 }
+
+func Test_WhenClearingAllValues_ThenTheOptionsAreNoLongerParsed(t *testing.T) {
+	repository := data.NewRepository()
+	repository.SaveOption("o", "opt", "description", "")
+	repository.GetOption("o").SetParsed()
+	repository.ClearValues()
+	actual := repository.GetOption("o").IsParsed()
+	if actual {
+		t.Errorf("Expected <false>, but got <true>")
+	}
+}
+
+func Test_WhenSavingMultipleOptionValues_ThenAllValuesCanBeRetrievedInOrder(t *testing.T) {
+	repository := data.NewRepository()
+	repository.SaveRepeatableOption("I", "include", "description", 0, 2, "")
+	repository.SaveOptionValue("I", "first")
+	repository.SaveOptionValue("include", "second")
+	actual := repository.GetOptionValues("I")
+	if len(actual) != 2 || actual[0] != "first" || actual[1] != "second" {
+		t.Errorf("Expected <[first second]>, but got <%v>", actual)
+	}
+}
+
+func Test_WhenSavingMultipleOptionValues_ThenTheLastValueIsTheOptionValue(t *testing.T) {
+	repository := data.NewRepository()
+	repository.SaveRepeatableOption("I", "include", "description", 0, 2, "")
+	repository.SaveOptionValue("I", "first")
+	repository.SaveOptionValue("I", "second")
+	actual := repository.GetOptionValue("I")
+	if actual != "second" {
+		t.Errorf("Expected <second>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenSavingOptionSources_ThenTheyCanBeRetrievedInOrder(t *testing.T) {
+	repository := data.NewRepository()
+	repository.SaveRepeatableOption("I", "include", "description", 0, 2, "")
+	repository.SaveOptionSource("I", model.NewCommandLineSource(0))
+	repository.SaveOptionSource("include", model.NewEnvironmentSource("INCLUDE"))
+	actual := repository.GetOptionSources("I")
+	if len(actual) != 2 || actual[0].Kind != model.CommandLine || actual[1].Kind != model.EnvironmentVariable {
+		t.Errorf("Expected <[CommandLine EnvironmentVariable]>, but got <%v>", actual)
+	}
+}
+
+func Test_WhenClearingValues_ThenArgumentSourcesAreCleared(t *testing.T) {
+	repository := data.NewRepository()
+	repository.SaveArgument("ARG", "description", 1, 1, "")
+	repository.SaveArgumentSource("ARG", model.NewCommandLineSource(0))
+	repository.ClearValues()
+	actual := repository.GetArgumentSources("ARG")
+	if len(actual) != 0 {
+		t.Errorf("Expected <[]>, but got <%v>", actual)
+	}
+}
@@ -0,0 +1,49 @@
+package data_test
+
+import (
+	"testing"
+
+	"github.com/echsylon/go-args/internal/data"
+)
+
+func Test_WhenGettingParentOptionFromScopedRepository_ThenTheParentOptionIsReturned(t *testing.T) {
+	parent := data.NewRepository()
+	parent.SaveOption("v", "verbose", "description", "")
+	scope := data.NewScopedRepository(parent)
+	option := scope.GetOption("verbose")
+	if option == nil {
+		t.Errorf("Expected <option>, but got <nil>")
+	}
+}
+
+func Test_WhenSavingParentOptionValueInScopedRepository_ThenTheValueIsSavedInTheParent(t *testing.T) {
+	parent := data.NewRepository()
+	parent.SaveOption("l", "level", "description", "")
+	scope := data.NewScopedRepository(parent)
+	scope.SaveOptionValue("level", "3")
+	actual := parent.GetOptionValue("level")
+	if actual != "3" {
+		t.Errorf("Expected <3>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenGettingOptionsFromScopedRepository_ThenLocalOptionsAreFollowedByInheritedOptions(t *testing.T) {
+	parent := data.NewRepository()
+	parent.SaveOption("v", "verbose", "description", "")
+	scope := data.NewScopedRepository(parent)
+	scope.SaveOption("f", "force", "description", "")
+	actual := scope.GetOptions()
+	if len(actual) != 2 || actual[0].GetLongName() != "force" || actual[1].GetLongName() != "verbose" {
+		t.Errorf("Expected <[force verbose]>, but got <%v>", actual)
+	}
+}
+
+func Test_WhenGettingArgumentsFromScopedRepository_ThenParentArgumentsAreNotInherited(t *testing.T) {
+	parent := data.NewRepository()
+	parent.SaveArgument("ARG", "description", 1, 1, "")
+	scope := data.NewScopedRepository(parent)
+	actual := scope.GetArgument("ARG")
+	if actual != nil {
+		t.Errorf("Expected <nil>, but got <%v>", actual)
+	}
+}
@@ -0,0 +1,68 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/echsylon/go-args/internal/domain"
+)
+
+func Test_WhenFormattingUnknownOptionError_ThenTheTokenIsIncluded(t *testing.T) {
+	expected := "unknown option: --undefined"
+	err := &domain.ParseError{Kind: domain.UnknownOption, Token: "--undefined", Index: 0}
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenFormattingTooManyValuesError_ThenTheDefinitionAndTokenAreIncluded(t *testing.T) {
+	expected := "too many values for FILES: file3.txt"
+	err := &domain.ParseError{Kind: domain.TooManyValues, Token: "file3.txt", Index: 2, Definition: "FILES"}
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenFormattingMissingArgumentError_ThenTheDefinitionIsIncluded(t *testing.T) {
+	expected := "missing input for: ARG1, ARG2"
+	err := &domain.ParseError{Kind: domain.MissingArgument, Index: -1, Definition: "ARG1, ARG2"}
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenFormattingHelpRequestedError_ThenEmptyStringIsReturned(t *testing.T) {
+	err := &domain.ParseError{Kind: domain.HelpRequested, Token: "--help", Index: 0, Definition: "help"}
+	actual := err.Error()
+	if actual != "" {
+		t.Errorf("Expected <>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenFormattingErrorKind_ThenItsNameIsReturned(t *testing.T) {
+	expected := "UnmatchedValue"
+	actual := domain.UnmatchedValue.String()
+	if actual != expected {
+		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenFormattingInvalidConfigErrorWithSource_ThenTheSourceIsIncluded(t *testing.T) {
+	expected := "app.json:2: invalid config file: unexpected end of input"
+	err := &domain.ParseError{Kind: domain.InvalidConfig, Token: "unexpected end of input", Index: -1, Source: "app.json:2"}
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenFormattingErrorWithSuggestion_ThenTheSuggestionIsIncluded(t *testing.T) {
+	expected := "unknown option: --verbsoe (did you mean --verbose?)"
+	err := &domain.ParseError{Kind: domain.UnknownOption, Token: "--verbsoe", Index: 0, Suggestion: "--verbose"}
+	actual := err.Error()
+	if actual != expected {
+		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
+	}
+}
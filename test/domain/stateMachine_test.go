@@ -21,17 +21,20 @@ type mockRepository struct {
 	optionValueListener   func(string, string)
 }
 
-func (mock *mockRepository) SaveArgument(string, string, int, int, string) {}
-func (mock *mockRepository) GetArgument(string) model.Argument             { return mock.argumentProvider() }
-func (mock *mockRepository) GetArguments() []model.Argument                { return mock.argumentsProvider() }
-func (mock *mockRepository) GetArgumentValues(string) []string             { return mock.argumentValueProvider() }
-func (mock *mockRepository) SaveArgumentValue(k string, v string)          { mock.argumentValueListener(k, v) }
-func (mock *mockRepository) SaveOption(string, string, string, string)     {}
-func (mock *mockRepository) GetOption(string) model.Option                 { return mock.optionProvider() }
-func (mock *mockRepository) GetOptions() []model.Option                    { return mock.optionsProvider() }
-func (mock *mockRepository) ClearValues()                                  {}
-func (mock *mockRepository) SaveOptionValue(k string, v string)            { mock.optionValueListener(k, v) }
-func (mock *mockRepository) GetOptionValue(string) string                  { return mock.optionValueProvider() }
+func (mock *mockRepository) SaveArgument(string, string, int, int, string)                 {}
+func (mock *mockRepository) GetArgument(string) model.Argument                             { return mock.argumentProvider() }
+func (mock *mockRepository) GetArguments() []model.Argument                                { return mock.argumentsProvider() }
+func (mock *mockRepository) GetArgumentValues(string) []string                             { return mock.argumentValueProvider() }
+func (mock *mockRepository) SaveArgumentValue(k string, v string)                          { mock.argumentValueListener(k, v) }
+func (mock *mockRepository) SaveOption(string, string, string, string)                     {}
+func (mock *mockRepository) SaveRepeatableOption(string, string, string, int, int, string) {}
+func (mock *mockRepository) GetOption(string) model.Option                                 { return mock.optionProvider() }
+func (mock *mockRepository) GetOptions() []model.Option                                    { return mock.optionsProvider() }
+func (mock *mockRepository) ClearValues()                                                  {}
+func (mock *mockRepository) SaveOptionValue(k string, v string)                            { mock.optionValueListener(k, v) }
+func (mock *mockRepository) GetOptionValue(string) string                                  { return mock.optionValueProvider() }
+func (mock *mockRepository) SaveOptionSource(string, model.Source)                         {}
+func (mock *mockRepository) SaveArgumentSource(string, model.Source)                       {}
 
 func newEmptyMockRepository() *mockRepository {
 	return &mockRepository{
@@ -324,3 +327,144 @@ func Test_WhenRequestingValueForParsedOptionWithNoGivenValue_ThenBooleanTrueIsRe
 		t.Errorf("Expeted <%s>, but got <%s>", expected, actual)
 	}
 }
+
+func Test_WhenParsingExplicitInput_ThenThoseValuesAreSaved(t *testing.T) {
+	argument := model.NewArgument("ARG", "description", 1, 1, "")
+	actualValue := ""
+
+	mockRepository := newEmptyMockRepository()
+	mockRepository.argumentsProvider = func() []model.Argument { return []model.Argument{argument} }
+	mockRepository.argumentValueListener = func(string, v string) { actualValue = v }
+	mockRepository.argumentValueProvider = func() []string {
+		if actualValue == "" {
+			return []string{}
+		}
+		return []string{actualValue}
+	}
+
+	state := domain.NewStateMachine("", "", mockRepository)
+	err := state.ParseArgs([]string{"value"})
+
+	if err != nil || actualValue != "value" {
+		t.Errorf("Expected <nil> and <value>, but got <%v> and <%s>", err, actualValue)
+	}
+}
+
+func Test_WhenParsingUndefinedOption_ThenUnknownOptionErrorIsReturned(t *testing.T) {
+	argument := model.NewArgument("ARG", "description", 1, 1, "")
+
+	mockRepository := newEmptyMockRepository()
+	mockRepository.argumentsProvider = func() []model.Argument { return []model.Argument{argument} }
+
+	state := domain.NewStateMachine("", "", mockRepository)
+	err := state.ParseArgs([]string{"value", "--undefined"})
+	actual, isParseError := err.(*domain.ParseError)
+
+	if !isParseError || actual.Kind != domain.UnknownOption || actual.Token != "--undefined" || actual.Index != 1 {
+		t.Errorf("Expected <UnknownOption> for <--undefined> at <1>, but got <%v>", err)
+	}
+}
+
+func Test_WhenParsingValueMatchingOnlySaturatedArgument_ThenTooManyValuesErrorIsReturned(t *testing.T) {
+	argument := model.NewArgument("ARG", "description", 1, 1, "")
+
+	mockRepository := newEmptyMockRepository()
+	mockRepository.argumentsProvider = func() []model.Argument { return []model.Argument{argument} }
+	mockRepository.argumentValueProvider = func() []string { return []string{"first"} }
+
+	state := domain.NewStateMachine("", "", mockRepository)
+	err := state.ParseArgs([]string{"second"})
+	actual, isParseError := err.(*domain.ParseError)
+
+	if !isParseError || actual.Kind != domain.TooManyValues || actual.Definition != "ARG" {
+		t.Errorf("Expected <TooManyValues> for <ARG>, but got <%v>", err)
+	}
+}
+
+func Test_WhenParsingTooFewArgumentValues_ThenMissingArgumentErrorIsReturned(t *testing.T) {
+	argument := model.NewArgument("ARG", "description", 1, 1, "")
+
+	mockRepository := newEmptyMockRepository()
+	mockRepository.argumentsProvider = func() []model.Argument { return []model.Argument{argument} }
+
+	state := domain.NewStateMachine("", "", mockRepository)
+	err := state.ParseArgs([]string{})
+	actual, isParseError := err.(*domain.ParseError)
+
+	if !isParseError || actual.Kind != domain.MissingArgument || actual.Definition != "ARG" || actual.Index != -1 {
+		t.Errorf("Expected <MissingArgument> for <ARG>, but got <%v>", err)
+	}
+}
+
+func Test_WhenParsingLongOptionWithAttachedValue_ThenThatValueIsSaved(t *testing.T) {
+	option := model.NewOption("o", "output", "description", "")
+	actualName := ""
+	actualValue := ""
+
+	mockRepository := newEmptyMockRepository()
+	mockRepository.optionProvider = func() model.Option { return option }
+	mockRepository.optionValueListener = func(k string, v string) { actualName = k; actualValue = v }
+
+	state := domain.NewStateMachine("", "", mockRepository)
+	err := state.ParseArgs([]string{"--output=a=b"})
+
+	if err != nil || actualName != "output" || actualValue != "a=b" {
+		t.Errorf("Expected <nil>, <output> and <a=b>, but got <%v>, <%s> and <%s>", err, actualName, actualValue)
+	}
+}
+
+func Test_WhenDefiningRepeatableOptionWithNegativeMinCount_ThenErrorIsReturned(t *testing.T) {
+	state := domain.NewStateMachine("", "", newEmptyMockRepository())
+	err := state.DefineRepeatableOption("I", "include", "description", -1, 2, "")
+	if err == nil {
+		t.Errorf("Expected <error>, but got <nil>")
+	}
+}
+
+func Test_WhenDefiningRepeatableOptionWithZeroMaxCount_ThenErrorIsReturned(t *testing.T) {
+	state := domain.NewStateMachine("", "", newEmptyMockRepository())
+	err := state.DefineRepeatableOption("I", "include", "description", 0, 0, "")
+	if err == nil {
+		t.Errorf("Expected <error>, but got <nil>")
+	}
+}
+
+func Test_WhenDefiningRepeatableOptionWithValidRange_ThenNoErrorIsReturned(t *testing.T) {
+	state := domain.NewStateMachine("", "", newEmptyMockRepository())
+	err := state.DefineRepeatableOption("I", "include", "description", 0, 2, "")
+	if err != nil {
+		t.Errorf("Expected <nil>, but got <error>: %s", err.Error())
+	}
+}
+
+func Test_WhenSettingDefaultValueForUndefinedOption_ThenErrorIsReturned(t *testing.T) {
+	state := domain.NewStateMachine("", "", newEmptyMockRepository())
+	err := state.SetOptionDefaultValue("undefined", "value")
+	if err == nil {
+		t.Errorf("Expected <error>, but got <nil>")
+	}
+}
+
+func Test_WhenSettingDefaultValueForCounterOption_ThenErrorIsReturned(t *testing.T) {
+	option := model.NewCounterOption("v", "verbose", "description")
+	mockRepository := newEmptyMockRepository()
+	mockRepository.optionProvider = func() model.Option { return option }
+
+	state := domain.NewStateMachine("", "", mockRepository)
+	err := state.SetOptionDefaultValue("verbose", "2")
+	if err == nil {
+		t.Errorf("Expected <error>, but got <nil>")
+	}
+}
+
+func Test_WhenSettingInvalidEnvironmentVariableForOption_ThenErrorIsReturned(t *testing.T) {
+	option := model.NewOption("p", "port", "description", "")
+	mockRepository := newEmptyMockRepository()
+	mockRepository.optionProvider = func() model.Option { return option }
+
+	state := domain.NewStateMachine("", "", mockRepository)
+	err := state.SetOptionEnvironmentVariable("port", "1PORT")
+	if err == nil {
+		t.Errorf("Expected <error>, but got <nil>")
+	}
+}
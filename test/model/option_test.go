@@ -76,3 +76,58 @@ func Test_WhenCreatingNewOption_ThenItsDescriptionCanBeRetrievedUndistorted(t *t
 		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
 	}
 }
+
+func Test_WhenCreatingNewOption_ThenItAcceptsAtMostOneOccurrence(t *testing.T) {
+	opt := model.NewOption("n", "name", "description", "")
+	min := opt.GetMinValuesCount()
+	max := opt.GetMaxValuesCount()
+	if min != 0 || max != 1 {
+		t.Errorf("Expected <0> and <1>, but got <%d> and <%d>", min, max)
+	}
+}
+
+func Test_WhenCreatingNewRepeatableOption_ThenItsCountRangeCanBeRetrievedUndistorted(t *testing.T) {
+	opt := model.NewRepeatableOption("I", "include", "description", 1, 3, "")
+	min := opt.GetMinValuesCount()
+	max := opt.GetMaxValuesCount()
+	if min != 1 || max != 3 {
+		t.Errorf("Expected <1> and <3>, but got <%d> and <%d>", min, max)
+	}
+}
+
+func Test_WhenSettingTheParsedFlagRepeatedly_ThenTheParsedCountIsIncreased(t *testing.T) {
+	opt := model.NewRepeatableOption("I", "include", "description", 0, 3, "")
+	opt.SetParsed()
+	opt.SetParsed()
+	actual := opt.GetParsedCount()
+	if actual != 2 {
+		t.Errorf("Expected <2>, but got <%d>", actual)
+	}
+}
+
+func Test_WhenCreatingNewCounterOption_ThenIsCounterAndIsRepeatableReturnTrue(t *testing.T) {
+	opt := model.NewCounterOption("v", "verbose", "description")
+	counter := opt.IsCounter()
+	repeatable := opt.IsRepeatable()
+	if !counter || !repeatable {
+		t.Errorf("Expected <true> and <true>, but got <%t> and <%t>", counter, repeatable)
+	}
+}
+
+func Test_WhenCreatingNewBooleanOption_ThenIsNegatableReturnsTrue(t *testing.T) {
+	opt := model.NewBooleanOption("c", "color", "description")
+	actual := opt.IsNegatable()
+	if !actual {
+		t.Errorf("Expected <true>, but got <false>")
+	}
+}
+
+func Test_WhenSettingEnvironmentVariableWithPrefix_ThenThePrefixedNameIsReturned(t *testing.T) {
+	opt := model.NewOption("p", "port", "description", "")
+	opt.SetEnvironmentVariable("PORT")
+	opt.SetEnvironmentPrefix("APP_")
+	actual := opt.GetEnvironmentVariable()
+	if actual != "APP_PORT" {
+		t.Errorf("Expected <APP_PORT>, but got <%s>", actual)
+	}
+}
@@ -0,0 +1,31 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/echsylon/go-args/internal/model"
+)
+
+func Test_WhenDescribingCommandLineSource_ThenTheIndexIsIncluded(t *testing.T) {
+	expected := "command line, index 2"
+	actual := model.NewCommandLineSource(2).String()
+	if actual != expected {
+		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenDescribingConfigFileSource_ThenThePathAndLineAreIncluded(t *testing.T) {
+	expected := "config file app.json:3"
+	actual := model.NewConfigFileSource("app.json", 3).String()
+	if actual != expected {
+		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenDescribingZeroSource_ThenItIsNotGiven(t *testing.T) {
+	expected := "not given"
+	actual := model.Source{}.String()
+	if actual != expected {
+		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
+	}
+}
@@ -0,0 +1,1869 @@
+package args_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/echsylon/go-args"
+)
+
+func Test_WhenCreatingNewParser_ThenItHasNoDefinitionsFromTheDefaultParser(t *testing.T) {
+	actualArgs := os.Args
+	defer func() { os.Args = actualArgs }()
+
+	os.Args = []string{"appName", "--value", "text"}
+
+	args.Reset()
+	args.DefineOption("value", "description")
+	parser := args.NewParser("other", "description")
+	parser.DefineOption("other", "description")
+	args.Parse()
+	actual := parser.GetOptionValue("value", "fallback")
+
+	if actual != "fallback" {
+		t.Errorf("Expected <fallback>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenParsingWithTwoParsers_ThenTheirValuesDoNotGetMixedUp(t *testing.T) {
+	actualArgs := os.Args
+	defer func() { os.Args = actualArgs }()
+
+	os.Args = []string{"appName", "-v", "text"}
+
+	first := args.NewParser("first", "")
+	first.DefineOption("v", "description")
+	second := args.NewParser("second", "")
+	second.DefineOptionStrict("v", "", "description", `^\d+$`)
+	second.DefineArgument("ARG", "description")
+	first.Parse()
+	second.Parse()
+	option := first.GetOptionValue("v", "fallback")
+	argument := second.GetArgumentValues("ARG")
+
+	if option != "text" || len(argument) != 1 || argument[0] != "text" {
+		t.Errorf("Expected <text> and <[text]>, but got <%s> and <%v>", option, argument)
+	}
+}
+
+func Test_WhenParsingExplicitInput_ThenTheCommandLineIsIgnored(t *testing.T) {
+	actualArgs := os.Args
+	defer func() { os.Args = actualArgs }()
+
+	os.Args = []string{"appName", "--undefined"}
+
+	parser := args.NewParser("app", "")
+	parser.DefineArgument("ARG", "description")
+	err := parser.ParseArgs([]string{"value"})
+	actual := parser.GetArgumentValues("ARG")
+
+	if err != nil || len(actual) != 1 || actual[0] != "value" {
+		t.Errorf("Expected <nil> and <[value]>, but got <%v> and <%v>", err, actual)
+	}
+}
+
+func Test_WhenParsingInvalidExplicitInput_ThenErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOption("v", "description")
+	err := parser.ParseArgs([]string{"--undefined"})
+
+	if err == nil {
+		t.Errorf("Expected <error>, but got <nil>")
+	}
+}
+
+func Test_WhenParsingExplicitInputTwice_ThenOnlyTheLatestValuesAreKept(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOption("v", "description")
+	parser.DefineArgumentStrict("ARG", "description", 1, 2, "")
+	parser.ParseArgs([]string{"-v", "first"})
+	parser.ParseArgs([]string{"second"})
+	option := parser.GetOptionValue("v", "fallback")
+	argument := parser.GetArgumentValues("ARG")
+
+	if option != "fallback" || len(argument) != 1 || argument[0] != "second" {
+		t.Errorf("Expected <fallback> and <[second]>, but got <%s> and <%v>", option, argument)
+	}
+}
+
+func Test_WhenTryParsingInvalidInput_ThenParseErrorIsReturned(t *testing.T) {
+	actualArgs := os.Args
+	defer func() { os.Args = actualArgs }()
+
+	os.Args = []string{"appName", "12", "text"}
+
+	parser := args.NewParser("app", "")
+	parser.DefineArgumentStrict("NUMBER", "description", 1, 1, `^\d+$`)
+	err := parser.TryParse()
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.UnmatchedValue || actual.Token != "text" || actual.Index != 1 {
+		t.Errorf("Expected <UnmatchedValue> for <text> at <1>, but got <%v>", err)
+	}
+}
+
+func Test_WhenTryParsingHelpOption_ThenHelpRequestedErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionHelp("h", "help", "description")
+	err := parser.ParseArgs([]string{"-h"})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.HelpRequested || actual.Definition != "help" {
+		t.Errorf("Expected <HelpRequested> for <help>, but got <%v>", err)
+	}
+}
+
+func Test_WhenTryParsingValidInput_ThenNilIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineArgument("ARG", "description")
+	err := parser.ParseArgs([]string{"value"})
+
+	if err != nil {
+		t.Errorf("Expected <nil>, but got <%v>", err)
+	}
+}
+
+func Test_WhenParsingInvalidInput_ThenErrorIsPrintedToStderrAndUsageExitCodeIsUsed(t *testing.T) {
+	actualArgs := os.Args
+	defer func() { os.Args = actualArgs }()
+
+	os.Args = []string{"appName", "--undefined"}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	code := -1
+
+	parser := args.NewParser("app", "")
+	parser.SetOutput(stdout, stderr)
+	parser.SetExitFunction(func(c int) { code = c })
+	parser.Parse()
+
+	if code != 2 || stdout.Len() != 0 || !strings.HasPrefix(stderr.String(), "unknown option: --undefined") {
+		t.Errorf("Expected <2>, <> and <unknown option...>, but got <%d>, <%s> and <%s>", code, stdout, stderr)
+	}
+}
+
+func Test_WhenParsingInvalidInputWithCustomUsageExitCode_ThenThatCodeIsUsed(t *testing.T) {
+	actualArgs := os.Args
+	defer func() { os.Args = actualArgs }()
+
+	os.Args = []string{"appName"}
+	code := -1
+
+	parser := args.NewParser("app", "")
+	parser.DefineArgument("ARG", "description")
+	parser.SetOutput(&bytes.Buffer{}, &bytes.Buffer{})
+	parser.SetExitFunction(func(c int) { code = c })
+	parser.SetUsageExitCode(64)
+	parser.Parse()
+
+	if code != 64 {
+		t.Errorf("Expected <64>, but got <%d>", code)
+	}
+}
+
+func Test_WhenParsingHelpOption_ThenHelpIsPrintedToStdoutAndZeroExitCodeIsUsed(t *testing.T) {
+	actualArgs := os.Args
+	defer func() { os.Args = actualArgs }()
+
+	os.Args = []string{"appName", "--help"}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	code := -1
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionHelp("h", "help", "description")
+	parser.SetOutput(stdout, stderr)
+	parser.SetExitFunction(func(c int) { code = c })
+	parser.Parse()
+
+	if code != 0 || stderr.Len() != 0 || !strings.HasPrefix(stdout.String(), "Usage: app") {
+		t.Errorf("Expected <0>, <Usage: app...> and <>, but got <%d>, <%s> and <%s>", code, stdout, stderr)
+	}
+}
+
+func Test_WhenParsingValidInput_ThenExitFunctionIsNotCalled(t *testing.T) {
+	actualArgs := os.Args
+	defer func() { os.Args = actualArgs }()
+
+	os.Args = []string{"appName", "value"}
+	called := false
+
+	parser := args.NewParser("app", "")
+	parser.DefineArgument("ARG", "description")
+	parser.SetExitFunction(func(int) { called = true })
+	parser.Parse()
+
+	if called {
+		t.Errorf("Expected <false>, but got <true>")
+	}
+}
+
+func Test_WhenParsingLongOptionWithAttachedValue_ThenTheValueIsAssigned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("o", "output", "description", "")
+	err := parser.ParseArgs([]string{"--output=file.txt"})
+	actual := parser.GetOptionValue("o", "fallback")
+
+	if err != nil || actual != "file.txt" {
+		t.Errorf("Expected <nil> and <file.txt>, but got <%v> and <%s>", err, actual)
+	}
+}
+
+func Test_WhenParsingShortOptionWithAttachedValue_ThenTheValueIsAssigned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("o", "output", "description", "")
+	err := parser.ParseArgs([]string{"-ofile.txt"})
+	actual := parser.GetOptionValue("output", "fallback")
+
+	if err != nil || actual != "file.txt" {
+		t.Errorf("Expected <nil> and <file.txt>, but got <%v> and <%s>", err, actual)
+	}
+}
+
+func Test_WhenParsingAttachedValueNotMatchingPattern_ThenUnmatchedValueErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("m", "max", "description", `^\d+$`)
+	parser.DefineArgument("ARG", "description")
+	err := parser.ParseArgs([]string{"--max=many", "value"})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.UnmatchedValue || actual.Token != "--max=many" || actual.Definition != "max" {
+		t.Errorf("Expected <UnmatchedValue> for <--max=many>, but got <%v>", err)
+	}
+}
+
+func Test_WhenParsingDetachedValueNotMatchingPattern_ThenTheValueIsAssignedToArgument(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("m", "max", "description", `^\d+$`)
+	parser.DefineArgument("ARG", "description")
+	err := parser.ParseArgs([]string{"--max", "many"})
+	option := parser.GetOptionValue("max", "fallback")
+	argument := parser.GetArgumentValues("ARG")
+
+	if err != nil || option != "true" || len(argument) != 1 || argument[0] != "many" {
+		t.Errorf("Expected <nil>, <true> and <[many]>, but got <%v>, <%s> and <%v>", err, option, argument)
+	}
+}
+
+func Test_WhenParsingClusteredShortOptions_ThenEachOptionIsParsed(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOption("v", "description")
+	parser.DefineOption("q", "description")
+	parser.DefineOption("x", "description")
+	err := parser.ParseArgs([]string{"-vqx"})
+	v := parser.GetOptionBoolValue("v", false)
+	q := parser.GetOptionBoolValue("q", false)
+	x := parser.GetOptionBoolValue("x", false)
+
+	if err != nil || !v || !q || !x {
+		t.Errorf("Expected <nil>, <true>, <true> and <true>, but got <%v>, <%t>, <%t> and <%t>", err, v, q, x)
+	}
+}
+
+func Test_WhenParsingClusteredShortOptions_ThenTheLastOptionCanConsumeTheNextValue(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOption("v", "description")
+	parser.DefineOption("o", "description")
+	err := parser.ParseArgs([]string{"-vo", "out.txt"})
+	v := parser.GetOptionValue("v", "fallback")
+	o := parser.GetOptionValue("o", "fallback")
+
+	if err != nil || v != "true" || o != "out.txt" {
+		t.Errorf("Expected <nil>, <true> and <out.txt>, but got <%v>, <%s> and <%s>", err, v, o)
+	}
+}
+
+func Test_WhenParsingClusterEndingWithUndefinedCharacters_ThenTheyAreAssignedAsValue(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOption("v", "description")
+	parser.DefineOption("o", "description")
+	err := parser.ParseArgs([]string{"-vout.txt"})
+	v := parser.GetOptionValue("v", "fallback")
+	o := parser.GetOptionValue("o", "fallback")
+
+	if err != nil || v != "out.txt" || o != "fallback" {
+		t.Errorf("Expected <nil>, <out.txt> and <fallback>, but got <%v>, <%s> and <%s>", err, v, o)
+	}
+}
+
+func Test_WhenParsingClusterStartingWithUndefinedShortOption_ThenUnknownOptionErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOption("v", "description")
+	parser.DefineOption("q", "description")
+	err := parser.ParseArgs([]string{"-zq"})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.UnknownOption || actual.Token != "-zq" {
+		t.Errorf("Expected <UnknownOption> for <-zq>, but got <%v>", err)
+	}
+}
+
+func Test_WhenParsingValuesAfterEndOfOptions_ThenTheyAreAssignedToArguments(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOption("v", "description")
+	parser.DefineArgumentStrict("FILES", "description", 1, 3, "")
+	err := parser.ParseArgs([]string{"-v", "--", "-report.txt", "--", "-v"})
+	option := parser.GetOptionValue("v", "fallback")
+	files := parser.GetArgumentValues("FILES")
+
+	if err != nil || option != "true" || len(files) != 3 || files[0] != "-report.txt" || files[1] != "--" || files[2] != "-v" {
+		t.Errorf("Expected <nil>, <true> and <[-report.txt -- -v]>, but got <%v>, <%s> and <%v>", err, option, files)
+	}
+}
+
+func Test_WhenParsingValueAfterEndOfOptions_ThenPendingOptionDoesNotReceiveIt(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOption("o", "description")
+	parser.DefineArgument("ARG", "description")
+	err := parser.ParseArgs([]string{"-o", "--", "value"})
+	option := parser.GetOptionValue("o", "fallback")
+	argument := parser.GetArgumentValues("ARG")
+
+	if err != nil || option != "true" || len(argument) != 1 || argument[0] != "value" {
+		t.Errorf("Expected <nil>, <true> and <[value]>, but got <%v>, <%s> and <%v>", err, option, argument)
+	}
+}
+
+func Test_WhenParsingNegativeNumbers_ThenTheyAreTreatedAsValues(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("o", "offset", "description", `^-?\d+$`)
+	parser.DefineArgumentStrict("NUMBERS", "description", 1, 2, `^-?\d+(\.\d+)?$`)
+	err := parser.ParseArgs([]string{"-2.5", "--offset", "-5", "-7"})
+	option := parser.GetOptionIntValue("offset", 0)
+	numbers := parser.GetArgumentFloatValues("NUMBERS")
+
+	if err != nil || option != -5 || len(numbers) != 2 || numbers[0] != -2.5 || numbers[1] != -7 {
+		t.Errorf("Expected <nil>, <-5> and <[-2.5 -7]>, but got <%v>, <%d> and <%v>", err, option, numbers)
+	}
+}
+
+func Test_WhenParsingRepeatableOption_ThenAllValuesAreCollectedInOrder(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionRepeatable("I", "include", "description", 0, 3, "")
+	err := parser.ParseArgs([]string{"-I", "a", "--include=b", "-Ic"})
+	values := parser.GetOptionValues("include")
+	last := parser.GetOptionValue("I", "fallback")
+
+	if err != nil || len(values) != 3 || values[0] != "a" || values[1] != "b" || values[2] != "c" || last != "c" {
+		t.Errorf("Expected <nil>, <[a b c]> and <c>, but got <%v>, <%v> and <%s>", err, values, last)
+	}
+}
+
+func Test_WhenParsingRepeatableOptionTooManyTimes_ThenTooManyValuesErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionRepeatable("I", "include", "description", 0, 2, "")
+	err := parser.ParseArgs([]string{"-I", "a", "-I", "b", "-I", "c"})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.TooManyValues || actual.Index != 4 || actual.Definition != "include" {
+		t.Errorf("Expected <TooManyValues> for <include> at <4>, but got <%v>", err)
+	}
+}
+
+func Test_WhenParsingRepeatableOptionTooFewTimes_ThenMissingArgumentErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionRepeatable("I", "include", "description", 2, 3, "")
+	err := parser.ParseArgs([]string{"-I", "a"})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.MissingArgument || actual.Definition != "--include" {
+		t.Errorf("Expected <MissingArgument> for <--include>, but got <%v>", err)
+	}
+}
+
+func Test_WhenParsingSingleOptionTwice_ThenTooManyValuesErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOption("v", "description")
+	err := parser.ParseArgs([]string{"-v", "-v"})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.TooManyValues || actual.Index != 1 {
+		t.Errorf("Expected <TooManyValues> at <1>, but got <%v>", err)
+	}
+}
+
+func Test_WhenParsingCounterOption_ThenEachOccurrenceIsCounted(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionCounter("v", "verbose", "description")
+	parser.DefineArgument("ARG", "description")
+	err := parser.ParseArgs([]string{"-vv", "--verbose", "value", "-v"})
+	count := parser.GetOptionCount("verbose")
+	argument := parser.GetArgumentValues("ARG")
+
+	if err != nil || count != 4 || len(argument) != 1 || argument[0] != "value" {
+		t.Errorf("Expected <nil>, <4> and <[value]>, but got <%v>, <%d> and <%v>", err, count, argument)
+	}
+}
+
+func Test_WhenParsingCounterOptionWithAttachedValue_ThenUnmatchedValueErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionCounter("v", "verbose", "description")
+	err := parser.ParseArgs([]string{"--verbose=2"})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.UnmatchedValue || actual.Definition != "verbose" {
+		t.Errorf("Expected <UnmatchedValue> for <verbose>, but got <%v>", err)
+	}
+}
+
+func Test_WhenRequestingCountForOptionNotParsed_ThenZeroIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionCounter("v", "verbose", "description")
+	parser.ParseArgs([]string{})
+	defined := parser.GetOptionCount("v")
+	undefined := parser.GetOptionCount("undefined")
+
+	if defined != 0 || undefined != 0 {
+		t.Errorf("Expected <0> and <0>, but got <%d> and <%d>", defined, undefined)
+	}
+}
+
+func Test_WhenParsingBooleanOption_ThenTrueIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionBool("c", "color", "description")
+	parser.DefineArgument("ARG", "description")
+	err := parser.ParseArgs([]string{"--color", "value"})
+	option := parser.GetOptionValue("color", "fallback")
+	argument := parser.GetArgumentValues("ARG")
+
+	if err != nil || option != "true" || len(argument) != 1 || argument[0] != "value" {
+		t.Errorf("Expected <nil>, <true> and <[value]>, but got <%v>, <%s> and <%v>", err, option, argument)
+	}
+}
+
+func Test_WhenParsingNegatedBooleanOption_ThenFalseIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionBool("c", "color", "description")
+	err := parser.ParseArgs([]string{"--no-color"})
+	actual := parser.GetOptionBoolValue("c", true)
+
+	if err != nil || actual != false {
+		t.Errorf("Expected <nil> and <false>, but got <%v> and <%t>", err, actual)
+	}
+}
+
+func Test_WhenParsingConflictingBooleanValues_ThenTheLastValueWinsByDefault(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionBool("c", "color", "description")
+	err := parser.ParseArgs([]string{"--no-color", "-c"})
+	actual := parser.GetOptionBoolValue("color", false)
+
+	if err != nil || actual != true {
+		t.Errorf("Expected <nil> and <true>, but got <%v> and <%t>", err, actual)
+	}
+}
+
+func Test_WhenParsingConflictingBooleanValuesWithRejectPolicy_ThenConflictingValuesErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionBool("c", "color", "description")
+	parser.SetConflictPolicy(args.RejectConflicts)
+	err := parser.ParseArgs([]string{"--color", "--color=true", "--no-color"})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.ConflictingValues || actual.Index != 2 || actual.Definition != "color" {
+		t.Errorf("Expected <ConflictingValues> for <color> at <2>, but got <%v>", err)
+	}
+}
+
+func Test_WhenParsingBooleanOptionWithNonBooleanValue_ThenUnmatchedValueErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionBool("c", "color", "description")
+	err := parser.ParseArgs([]string{"--color=blue"})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.UnmatchedValue {
+		t.Errorf("Expected <UnmatchedValue>, but got <%v>", err)
+	}
+}
+
+func Test_WhenDefiningOptionShadowingNegatedBooleanOption_ThenPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("<Expected <panic>, but got nothing")
+		}
+	}()
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionBool("c", "color", "description")
+	parser.DefineOption("no-color", "description")
+}
+
+func Test_WhenOptionWithDefaultValueIsNotParsed_ThenTheDefaultValueIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("m", "max", "description", `^\d+$`)
+	parser.SetOptionDefaultValue("max", "2")
+	parser.ParseArgs([]string{})
+	actual := parser.GetOptionIntValue("m", 7)
+	values := parser.GetOptionValues("m")
+
+	if actual != 2 || len(values) != 1 || values[0] != "2" {
+		t.Errorf("Expected <2> and <[2]>, but got <%d> and <%v>", actual, values)
+	}
+}
+
+func Test_WhenOptionWithDefaultValueIsParsed_ThenTheParsedValueIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("m", "max", "description", `^\d+$`)
+	parser.SetOptionDefaultValue("max", "2")
+	parser.ParseArgs([]string{"-m", "5"})
+	actual := parser.GetOptionIntValue("m", 7)
+
+	if actual != 5 {
+		t.Errorf("Expected <5>, but got <%d>", actual)
+	}
+}
+
+func Test_WhenSettingDefaultValueNotMatchingOptionPattern_ThenPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("<Expected <panic>, but got nothing")
+		}
+	}()
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("m", "max", "description", `^\d+$`)
+	parser.SetOptionDefaultValue("max", "many")
+}
+
+func Test_WhenArgumentWithDefaultValuesIsNotParsed_ThenTheDefaultValuesAreReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineArgumentStrict("FILES", "description", 1, 2, `\.txt$`)
+	parser.SetArgumentDefaultValues("FILES", "a.txt", "b.txt")
+	err := parser.ParseArgs([]string{})
+	actual := parser.GetArgumentValues("FILES")
+
+	if err != nil || len(actual) != 2 || actual[0] != "a.txt" || actual[1] != "b.txt" {
+		t.Errorf("Expected <nil> and <[a.txt b.txt]>, but got <%v> and <%v>", err, actual)
+	}
+}
+
+func Test_WhenSettingTooManyDefaultValuesForArgument_ThenPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("<Expected <panic>, but got nothing")
+		}
+	}()
+
+	parser := args.NewParser("app", "")
+	parser.DefineArgument("ARG", "description")
+	parser.SetArgumentDefaultValues("ARG", "a", "b")
+}
+
+func Test_WhenRequiredOptionIsNotParsed_ThenMissingArgumentErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("r", "region", "description", "")
+	parser.SetOptionRequired("r")
+	err := parser.ParseArgs([]string{})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.MissingArgument || actual.Error() != "missing input for: --region" {
+		t.Errorf("Expected <missing input for: --region>, but got <%v>", err)
+	}
+}
+
+func Test_WhenRequiredOptionIsParsed_ThenNoErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("r", "region", "description", "")
+	parser.SetOptionRequired("region")
+	err := parser.ParseArgs([]string{"--region=eu"})
+
+	if err != nil {
+		t.Errorf("Expected <nil>, but got <%v>", err)
+	}
+}
+
+func Test_WhenRequiredOptionHasDefaultValue_ThenNoErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("r", "region", "description", "")
+	parser.SetOptionRequired("region")
+	parser.SetOptionDefaultValue("region", "eu")
+	err := parser.ParseArgs([]string{})
+
+	if err != nil {
+		t.Errorf("Expected <nil>, but got <%v>", err)
+	}
+}
+
+func Test_WhenOptionIsBoundToSetEnvironmentVariable_ThenTheEnvironmentValueIsReturned(t *testing.T) {
+	os.Setenv("GO_ARGS_TEST_PORT", "8080")
+	defer os.Unsetenv("GO_ARGS_TEST_PORT")
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("p", "port", "description", "")
+	parser.SetOptionEnvironmentVariable("port", "GO_ARGS_TEST_PORT")
+	parser.ParseArgs([]string{})
+
+	actual := parser.GetOptionValue("port", "")
+	if actual != "8080" {
+		t.Errorf("Expected <8080>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenBoundOptionIsAlsoParsed_ThenTheCommandLineValueWins(t *testing.T) {
+	os.Setenv("GO_ARGS_TEST_PORT", "8080")
+	defer os.Unsetenv("GO_ARGS_TEST_PORT")
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("p", "port", "description", "")
+	parser.SetOptionDefaultValue("port", "80")
+	parser.SetOptionEnvironmentVariable("port", "GO_ARGS_TEST_PORT")
+	parser.ParseArgs([]string{"--port", "9090"})
+
+	actual := parser.GetOptionValue("port", "")
+	if actual != "9090" {
+		t.Errorf("Expected <9090>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenEnvironmentPrefixIsSet_ThenItIsPrependedToTheVariableName(t *testing.T) {
+	os.Setenv("GO_ARGS_TEST_PORT", "8080")
+	defer os.Unsetenv("GO_ARGS_TEST_PORT")
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("p", "port", "description", "")
+	parser.SetOptionEnvironmentVariable("port", "PORT")
+	parser.SetEnvironmentPrefix("GO_ARGS_TEST_")
+	parser.ParseArgs([]string{})
+
+	actual := parser.GetOptionValue("port", "")
+	if actual != "8080" {
+		t.Errorf("Expected <8080>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenEnvironmentValueDoesNotMatchPattern_ThenInvalidValueErrorIsReturned(t *testing.T) {
+	os.Setenv("GO_ARGS_TEST_PORT", "http")
+	defer os.Unsetenv("GO_ARGS_TEST_PORT")
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("p", "port", "description", "^[0-9]+$")
+	parser.SetOptionEnvironmentVariable("port", "GO_ARGS_TEST_PORT")
+	err := parser.ParseArgs([]string{})
+	actual, isParseError := err.(*args.ParseError)
+
+	expected := "environment variable GO_ARGS_TEST_PORT: invalid value for port: http"
+	if !isParseError || actual.Kind != args.InvalidValue || actual.Error() != expected {
+		t.Errorf("Expected <%s>, but got <%v>", expected, err)
+	}
+}
+
+func Test_WhenRequiredArgumentIsBoundToSetEnvironmentVariable_ThenNoErrorIsReturned(t *testing.T) {
+	os.Setenv("GO_ARGS_TEST_FILE", "input.txt")
+	defer os.Unsetenv("GO_ARGS_TEST_FILE")
+
+	parser := args.NewParser("app", "")
+	parser.DefineArgument("FILE", "description")
+	parser.SetArgumentEnvironmentVariable("FILE", "GO_ARGS_TEST_FILE")
+	err := parser.ParseArgs([]string{})
+
+	if err != nil || parser.GetArgumentValues("FILE")[0] != "input.txt" {
+		t.Errorf("Expected <nil> and <input.txt>, but got <%v> and <%v>", err, parser.GetArgumentValues("FILE"))
+	}
+}
+
+func Test_WhenConfigFileHasOptionValue_ThenTheConfigValueIsReturned(t *testing.T) {
+	path, cleanup := writeTempFile(t, "app.json", `{"port": 8080}`)
+	defer cleanup()
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("p", "port", "description", "")
+	parser.SetConfigFile("", path)
+	err := parser.ParseArgs([]string{})
+
+	actual := parser.GetOptionValue("port", "")
+	if err != nil || actual != "8080" {
+		t.Errorf("Expected <nil> and <8080>, but got <%v> and <%s>", err, actual)
+	}
+}
+
+func Test_WhenConfigFileAndEnvironmentBothHaveValue_ThenTheEnvironmentValueWins(t *testing.T) {
+	path, cleanup := writeTempFile(t, "app.json", `{"port": 8080}`)
+	defer cleanup()
+	os.Setenv("GO_ARGS_TEST_PORT", "9090")
+	defer os.Unsetenv("GO_ARGS_TEST_PORT")
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("p", "port", "description", "")
+	parser.SetOptionDefaultValue("port", "80")
+	parser.SetOptionEnvironmentVariable("port", "GO_ARGS_TEST_PORT")
+	parser.SetConfigFile("", path)
+	parser.ParseArgs([]string{})
+
+	actual := parser.GetOptionValue("port", "")
+	if actual != "9090" {
+		t.Errorf("Expected <9090>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenConfigFileIsGivenByOption_ThenThatFileIsRead(t *testing.T) {
+	path, cleanup := writeTempFile(t, "app.json", `{"FILES": ["a.txt", "b.txt"]}`)
+	defer cleanup()
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("c", "config", "description", "")
+	parser.DefineArgumentStrict("FILES", "description", 1, 2, "")
+	parser.SetConfigFile("config", "")
+	err := parser.ParseArgs([]string{"--config", path})
+
+	actual := parser.GetArgumentValues("FILES")
+	if err != nil || len(actual) != 2 {
+		t.Errorf("Expected <nil> and <2> values, but got <%v> and <%v>", err, actual)
+	}
+}
+
+func Test_WhenDefaultConfigFileDoesNotExist_ThenNoErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("p", "port", "description", "")
+	parser.SetConfigFile("", "does-not-exist.json")
+	err := parser.ParseArgs([]string{})
+
+	if err != nil {
+		t.Errorf("Expected <nil>, but got <%v>", err)
+	}
+}
+
+func Test_WhenConfigFileValueDoesNotMatchPattern_ThenInvalidValueErrorWithLocationIsReturned(t *testing.T) {
+	path, cleanup := writeTempFile(t, "app.json", "{\n  \"port\": \"http\"\n}")
+	defer cleanup()
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("p", "port", "description", "^[0-9]+$")
+	parser.SetConfigFile("", path)
+	err := parser.ParseArgs([]string{})
+	actual, isParseError := err.(*args.ParseError)
+
+	expected := path + ":2: invalid value for port: http"
+	if !isParseError || actual.Kind != args.InvalidValue || actual.Error() != expected {
+		t.Errorf("Expected <%s>, but got <%v>", expected, err)
+	}
+}
+
+func Test_WhenConfigFileHasUnknownKey_ThenUnknownOptionErrorIsReturned(t *testing.T) {
+	path, cleanup := writeTempFile(t, "app.json", `{"undefined": "value"}`)
+	defer cleanup()
+
+	parser := args.NewParser("app", "")
+	parser.SetConfigFile("", path)
+	err := parser.ParseArgs([]string{})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.UnknownOption || actual.Source != path+":1" {
+		t.Errorf("Expected <UnknownOption> from <%s:1>, but got <%v>", path, err)
+	}
+}
+
+func Test_WhenConfigFileKeyIsShortOptionName_ThenUnknownOptionErrorIsReturned(t *testing.T) {
+	path, cleanup := writeTempFile(t, "app.json", `{"o": "out.txt"}`)
+	defer cleanup()
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("o", "output", "description", "")
+	parser.SetConfigFile("", path)
+	err := parser.ParseArgs([]string{})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.UnknownOption || actual.Token != "o" || parser.GetOptionValue("output", "") != "" {
+		t.Errorf("Expected <UnknownOption> for <o>, but got <%v> and <%s>", err, parser.GetOptionValue("output", ""))
+	}
+}
+
+func Test_WhenConfigFileKeyIsShortNameOfOptionWithoutLongName_ThenTheValueIsUsed(t *testing.T) {
+	path, cleanup := writeTempFile(t, "app.json", `{"o": "out.txt"}`)
+	defer cleanup()
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("o", "", "description", "")
+	parser.SetConfigFile("", path)
+	err := parser.ParseArgs([]string{})
+
+	if actual := parser.GetOptionValue("o", ""); err != nil || actual != "out.txt" {
+		t.Errorf("Expected <nil> and <out.txt>, but got <%v> and <%s>", err, actual)
+	}
+}
+
+func writeTempFile(t *testing.T, name string, content string) (string, func()) {
+	directory, err := ioutil.TempDir("", "go-args")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(directory, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path, func() { os.RemoveAll(directory) }
+}
+
+func Test_WhenConfigFileIsTOML_ThenTableKeysMatchPrefixedOptionNames(t *testing.T) {
+	path, cleanup := writeTempFile(t, "app.toml", "[server]\nport = 8080\n")
+	defer cleanup()
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("", "server.port", "description", "")
+	parser.SetConfigFile("", path)
+	err := parser.ParseArgs([]string{})
+
+	actual := parser.GetOptionIntValue("server.port", 0)
+	if err != nil || actual != 8080 {
+		t.Errorf("Expected <nil> and <8080>, but got <%v> and <%d>", err, actual)
+	}
+}
+
+func Test_WhenConfigFileIsINI_ThenCommandLineValueStillWins(t *testing.T) {
+	path, cleanup := writeTempFile(t, "app.ini", "[server]\nport = 8080\n")
+	defer cleanup()
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("", "server.port", "description", "")
+	parser.SetConfigFile("", path)
+	err := parser.ParseArgs([]string{"--server.port", "9090"})
+
+	actual := parser.GetOptionValue("server.port", "")
+	if err != nil || actual != "9090" {
+		t.Errorf("Expected <nil> and <9090>, but got <%v> and <%s>", err, actual)
+	}
+}
+
+func Test_WhenOptionIsParsed_ThenItsSourceIsTheCommandLineIndex(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("t", "timeout", "description", "")
+	parser.ParseArgs([]string{"--timeout", "30"})
+
+	actual := parser.GetOptionSource("timeout")
+	if actual.Kind != args.CommandLine || actual.Index != 0 {
+		t.Errorf("Expected <command line, index 0>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenOptionValueComesFromEachSource_ThenEachSourceIsReported(t *testing.T) {
+	path, cleanup := writeTempFile(t, "app.json", "{\n  \"timeout\": 30\n}")
+	defer cleanup()
+	os.Setenv("GO_ARGS_TEST_HOST", "localhost")
+	defer os.Unsetenv("GO_ARGS_TEST_HOST")
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("", "timeout", "description", "")
+	parser.DefineOptionStrict("", "host", "description", "")
+	parser.DefineOptionStrict("", "port", "description", "")
+	parser.DefineOptionStrict("", "user", "description", "")
+	parser.SetOptionEnvironmentVariable("host", "GO_ARGS_TEST_HOST")
+	parser.SetOptionDefaultValue("port", "80")
+	parser.SetConfigFile("", path)
+	parser.ParseArgs([]string{})
+
+	actual := []args.Source{
+		parser.GetOptionSource("timeout"),
+		parser.GetOptionSource("host"),
+		parser.GetOptionSource("port"),
+		parser.GetOptionSource("user"),
+	}
+	if actual[0].Kind != args.ConfigFile || actual[0].Line != 2 ||
+		actual[1].Kind != args.EnvironmentVariable ||
+		actual[2].Kind != args.DefaultValue ||
+		actual[3].Kind != args.NotGiven {
+		t.Errorf("Expected <ConfigFile EnvironmentVariable DefaultValue NotGiven>, but got <%v>", actual)
+	}
+}
+
+func Test_WhenArgumentsAreParsed_ThenEachValueHasItsOwnSource(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineOptionCounter("v", "", "description")
+	parser.DefineArgumentStrict("FILES", "description", 1, 2, "")
+	parser.ParseArgs([]string{"a.txt", "-v", "b.txt"})
+
+	actual := parser.GetArgumentSources("FILES")
+	if len(actual) != 2 || actual[0].Index != 0 || actual[1].Index != 2 {
+		t.Errorf("Expected <[0 2]> indices, but got <%v>", actual)
+	}
+}
+
+func Test_WhenDumpingValues_ThenAllDefinitionsAreListedWithTheirSources(t *testing.T) {
+	expected := "--timeout  30     (command line, index 0)\n" +
+		"-v         2      (command line, index 3)\n" +
+		"FILE       a.txt  (command line, index 2)"
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionHelp("h", "help", "description")
+	parser.DefineOptionStrict("t", "timeout", "description", "")
+	parser.DefineOptionCounter("v", "", "description")
+	parser.DefineArgument("FILE", "description")
+	parser.ParseArgs([]string{"--timeout", "30", "a.txt", "-vv"})
+
+	actual := parser.DumpValues()
+	if actual != expected {
+		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenResponseFilesAreEnabled_ThenFileTokensAreParsed(t *testing.T) {
+	path, cleanup := writeTempFile(t, "args.txt", "--name 'John Doe'\ninput.txt\n")
+	defer cleanup()
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("n", "name", "description", "")
+	parser.DefineArgument("FILE", "description")
+	parser.SetResponseFilesEnabled(true)
+	err := parser.ParseArgs([]string{"@" + path})
+
+	actual := parser.GetOptionValue("name", "")
+	source := parser.GetArgumentSources("FILE")[0]
+	if err != nil || actual != "John Doe" || source.Kind != args.ResponseFile || source.Line != 2 {
+		t.Errorf("Expected <nil>, <John Doe> and line <2>, but got <%v>, <%s> and <%s>", err, actual, source)
+	}
+}
+
+func Test_WhenResponseFilesAreDisabled_ThenAtTokensAreValues(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.DefineArgument("FILE", "description")
+	err := parser.ParseArgs([]string{"@args.txt"})
+
+	actual := parser.GetArgumentValues("FILE")
+	if err != nil || len(actual) != 1 || actual[0] != "@args.txt" {
+		t.Errorf("Expected <nil> and <[@args.txt]>, but got <%v> and <%v>", err, actual)
+	}
+}
+
+func Test_WhenResponseFileHasUnknownOption_ThenTheErrorReferencesTheFileAndLine(t *testing.T) {
+	path, cleanup := writeTempFile(t, "args.txt", "\n--undefined\n")
+	defer cleanup()
+
+	parser := args.NewParser("app", "")
+	parser.SetResponseFilesEnabled(true)
+	err := parser.ParseArgs([]string{"@" + path})
+
+	expected := path + ":2: unknown option: --undefined"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected <%s>, but got <%v>", expected, err)
+	}
+}
+
+func Test_WhenResponseFileIsMissing_ThenInvalidResponseFileErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("app", "")
+	parser.SetResponseFilesEnabled(true)
+	err := parser.ParseArgs([]string{"@does-not-exist.txt"})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.InvalidResponseFile || actual.Index != 0 {
+		t.Errorf("Expected <InvalidResponseFile> at index <0>, but got <%v>", err)
+	}
+}
+
+func Test_WhenCommandIsGiven_ThenItIsSelectedAndItsValuesAreParsed(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	build := parser.AddCommand("build", "description")
+	build.DefineOptionStrict("o", "output", "description", "")
+	build.DefineArgument("TARGET", "description")
+	parser.AddCommand("deploy", "description")
+	err := parser.ParseArgs([]string{"build", "-o", "out", "all"})
+
+	actual := parser.GetCommand()
+	if err != nil || actual != "build" || build.GetOptionValue("output", "") != "out" || build.GetArgumentValues("TARGET")[0] != "all" {
+		t.Errorf("Expected <nil>, <build>, <out> and <all>, but got <%v>, <%s>, <%s> and <%v>", err, actual, build.GetOptionValue("output", ""), build.GetArgumentValues("TARGET"))
+	}
+}
+
+func Test_WhenGlobalOptionIsGivenAfterCommand_ThenItIsInherited(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionCounter("v", "verbose", "description")
+	build := parser.AddCommand("build", "description")
+	err := parser.ParseArgs([]string{"-v", "build", "-v"})
+
+	if err != nil || parser.GetOptionCount("verbose") != 2 || build.GetOptionCount("verbose") != 2 {
+		t.Errorf("Expected <nil> and <2>, but got <%v> and <%d>", err, parser.GetOptionCount("verbose"))
+	}
+}
+
+func Test_WhenGlobalOptionWithValueIsGivenBeforeCommand_ThenTheCommandIsSelected(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineOption("verbose", "description")
+	parser.AddCommand("build", "description")
+	err := parser.ParseArgs([]string{"--verbose", "build"})
+
+	command := parser.GetCommand()
+	value := parser.GetOptionValue("verbose", "")
+	if err != nil || command != "build" || value != "true" {
+		t.Errorf("Expected <nil>, <build> and <true>, but got <%v>, <%s> and <%s>", err, command, value)
+	}
+}
+
+func Test_WhenCommandOptionIsGivenBeforeCommand_ThenUnknownOptionErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	build := parser.AddCommand("build", "description")
+	build.DefineOptionCounter("f", "force", "description")
+	err := parser.ParseArgs([]string{"--force", "build"})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.UnknownOption {
+		t.Errorf("Expected <UnknownOption>, but got <%v>", err)
+	}
+}
+
+func Test_WhenUnknownCommandIsGiven_ThenUnknownCommandErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("build", "description")
+	err := parser.ParseArgs([]string{"deploy"})
+	actual, isParseError := err.(*args.ParseError)
+
+	if !isParseError || actual.Kind != args.UnknownCommand || actual.Error() != "unknown command: deploy" {
+		t.Errorf("Expected <unknown command: deploy>, but got <%v>", err)
+	}
+}
+
+func Test_WhenNoCommandIsGiven_ThenMissingCommandErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("build", "description")
+	parser.AddCommand("deploy", "description")
+	err := parser.ParseArgs([]string{})
+	actual, isParseError := err.(*args.ParseError)
+
+	expected := "missing command, expected one of: build, deploy"
+	if !isParseError || actual.Kind != args.MissingCommand || actual.Error() != expected {
+		t.Errorf("Expected <%s>, but got <%v>", expected, err)
+	}
+}
+
+func Test_WhenParsingAgain_ThenThePreviousCommandSelectionIsPurged(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("build", "description")
+	parser.AddCommand("status", "description")
+	parser.ParseArgs([]string{"build"})
+	parser.ParseArgs([]string{"status"})
+
+	actual := parser.GetCommand()
+	if actual != "status" {
+		t.Errorf("Expected <status>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenDefiningArgumentNextToCommands_ThenPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected <panic>, but got <nil>")
+		}
+	}()
+
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("build", "description")
+	parser.DefineArgument("ARG", "description")
+}
+
+func Test_WhenHelpIsRequestedForCommand_ThenTheCommandHelpIsPrinted(t *testing.T) {
+	actualArgs := os.Args
+	defer func() { os.Args = actualArgs }()
+
+	var stdout bytes.Buffer
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionHelp("h", "help", "Show help.")
+	build := parser.AddCommand("build", "Build it.")
+	build.DefineOptionCounter("f", "force", "Force it.")
+	parser.AddCommand("deploy", "Deploy it.")
+	parser.SetOutput(&stdout, nil)
+	parser.SetExitFunction(func(code int) {})
+
+	os.Args = []string{"tool", "--help"}
+	parser.Parse()
+	root := stdout.String()
+	stdout.Reset()
+
+	build.SetExitFunction(func(code int) {})
+	os.Args = []string{"tool", "build", "--help"}
+	build.Parse()
+	command := stdout.String()
+
+	if !strings.Contains(root, "Usage: tool [OPTION] COMMAND") || !strings.Contains(root, "Commands:\n  build   Build it.\n  deploy  Deploy it.") {
+		t.Errorf("Expected root help with commands, but got <%s>", root)
+	}
+	if !strings.Contains(command, "Usage: tool build [OPTIONS...]") || !strings.Contains(command, "--force") {
+		t.Errorf("Expected build command help, but got <%s>", command)
+	}
+}
+
+func newHelpCommandParser(stdout *bytes.Buffer) *args.Parser {
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionHelp("h", "help", "Show help.")
+	deploy := parser.AddCommand("deploy", "Deploy it.")
+	deploy.DefineOptionCounter("f", "force", "Force it.")
+	parser.AddHelpCommand("help", "Show help.")
+	parser.AddHelpTopic("patterns", "Value patterns.", "Values are matched as regular expressions.")
+	parser.SetOutput(stdout, stdout)
+	return parser
+}
+
+func Test_WhenHelpCommandIsGiven_ThenTheRootHelpIsPrinted(t *testing.T) {
+	var stdout bytes.Buffer
+	parser := newHelpCommandParser(&stdout)
+	code := parser.Run([]string{"help"})
+
+	actual := stdout.String()
+	if code != 0 || !strings.Contains(actual, "Usage: tool [OPTION] COMMAND") || !strings.Contains(actual, "Help topics:\n  patterns  Value patterns.") {
+		t.Errorf("Expected <0> and root help with topics, but got <%d> and <%s>", code, actual)
+	}
+}
+
+func Test_WhenHelpCommandIsGivenWithCommand_ThenTheCommandHelpIsPrinted(t *testing.T) {
+	var stdout bytes.Buffer
+	parser := newHelpCommandParser(&stdout)
+	parser.Run([]string{"help", "deploy"})
+	viaCommand := stdout.String()
+	stdout.Reset()
+	parser.Run([]string{"deploy", "--help"})
+	viaOption := stdout.String()
+
+	if !strings.Contains(viaCommand, "Usage: tool deploy [OPTIONS...]") || viaCommand != viaOption {
+		t.Errorf("Expected <%s>, but got <%s>", viaOption, viaCommand)
+	}
+}
+
+func Test_WhenHelpCommandIsGivenWithTopic_ThenTheTopicTextIsPrinted(t *testing.T) {
+	var stdout bytes.Buffer
+	parser := newHelpCommandParser(&stdout)
+	code := parser.Run([]string{"help", "patterns"})
+
+	actual := stdout.String()
+	if code != 0 || actual != "Values are matched as regular expressions.\n" {
+		t.Errorf("Expected <0> and <Values are matched as regular expressions.>, but got <%d> and <%s>", code, actual)
+	}
+}
+
+func Test_WhenHelpCommandIsGivenWithUnknownName_ThenUnknownCommandErrorIsReturned(t *testing.T) {
+	var stdout bytes.Buffer
+	parser := newHelpCommandParser(&stdout)
+	err := parser.ParseArgs([]string{"help", "unknown"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.UnknownCommand || parseError.Token != "unknown" {
+		t.Errorf("Expected <UnknownCommand> for <unknown>, but got <%v>", err)
+	}
+}
+
+func Test_WhenRunningSelectedCommand_ThenItsHandlerIsCalledWithItsValues(t *testing.T) {
+	var actual string
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionStrict("", "region", "description", "")
+	deploy := parser.AddCommand("deploy", "description")
+	deploy.DefineArgument("TARGET", "description")
+	deploy.SetHandler(func(ctx args.Context) error {
+		actual = ctx.Command + ":" + ctx.GetOptionValue("region", "") + ":" + ctx.GetArgumentValues("TARGET")[0]
+		return nil
+	})
+	parser.AddCommand("status", "description").SetHandler(func(ctx args.Context) error {
+		t.Errorf("Expected <deploy> handler, but got <status> handler")
+		return nil
+	})
+	code := parser.Run([]string{"--region", "eu", "deploy", "web"})
+
+	if code != 0 || actual != "deploy:eu:web" {
+		t.Errorf("Expected <0> and <deploy:eu:web>, but got <%d> and <%s>", code, actual)
+	}
+}
+
+func Test_WhenNestedCommandHasNoHandler_ThenTheParentHandlerIsCalled(t *testing.T) {
+	var actual string
+	parser := args.NewParser("tool", "")
+	remote := parser.AddCommand("remote", "description")
+	remote.AddCommand("add", "description")
+	remote.SetHandler(func(ctx args.Context) error {
+		actual = ctx.Command
+		return nil
+	})
+	parser.Run([]string{"remote", "add"})
+
+	if actual != "remote add" {
+		t.Errorf("Expected <remote add>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenHandlerReturnsError_ThenItIsPrintedAndTheDefaultErrorExitCodeIsReturned(t *testing.T) {
+	var stderr bytes.Buffer
+	parser := args.NewParser("tool", "")
+	parser.SetOutput(nil, &stderr)
+	parser.SetHandler(func(ctx args.Context) error { return errors.New("failed") })
+	code := parser.Run([]string{})
+
+	actual := stderr.String()
+	if code != args.DefaultErrorExitCode || actual != "tool: failed\n" {
+		t.Errorf("Expected <1> and <tool: failed>, but got <%d> and <%s>", code, actual)
+	}
+}
+
+func Test_WhenHandlerReturnsErrorWithExitCode_ThenThatExitCodeIsReturned(t *testing.T) {
+	cause := errors.New("not found")
+	var unwrapped error
+	parser := args.NewParser("tool", "")
+	parser.SetOutput(nil, &bytes.Buffer{})
+	parser.SetHandler(func(ctx args.Context) error {
+		err := args.WithExitCode(cause, 3)
+		unwrapped = errors.Unwrap(err)
+		return err
+	})
+	code := parser.Run([]string{})
+
+	if code != 3 || unwrapped != cause {
+		t.Errorf("Expected <3> and <%v>, but got <%d> and <%v>", cause, code, unwrapped)
+	}
+}
+
+func Test_WhenRunningWithInvalidInput_ThenTheHandlerIsNotCalledAndTheUsageExitCodeIsReturned(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.SetOutput(nil, &bytes.Buffer{})
+	parser.SetHandler(func(ctx args.Context) error {
+		t.Errorf("Expected no handler call")
+		return nil
+	})
+	code := parser.Run([]string{"--undefined"})
+
+	if code != args.DefaultUsageExitCode {
+		t.Errorf("Expected <%d>, but got <%d>", args.DefaultUsageExitCode, code)
+	}
+}
+
+func Test_WhenExecuting_ThenTheExitFunctionIsCalledWithTheHandlerExitCode(t *testing.T) {
+	actualArgs := os.Args
+	defer func() { os.Args = actualArgs }()
+	os.Args = []string{"tool", "build"}
+
+	var actual = -1
+	parser := args.NewParser("tool", "")
+	parser.SetExitFunction(func(code int) { actual = code })
+	parser.AddCommand("build", "description").SetHandler(func(ctx args.Context) error { return nil })
+	parser.Execute()
+
+	if actual != 0 {
+		t.Errorf("Expected <0>, but got <%d>", actual)
+	}
+}
+
+func Test_WhenRunningWithoutAnyHandler_ThenPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected <panic>, but got <nil>")
+		}
+	}()
+
+	parser := args.NewParser("tool", "")
+	parser.Run([]string{})
+}
+
+func writePlugin(t *testing.T, name string, script string) (string, func()) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need a POSIX shell")
+	}
+
+	path, cleanup := writeTempFile(t, name, "#!/bin/sh\n"+script)
+	if err := os.Chmod(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Dir(path), cleanup
+}
+
+func Test_WhenPluginIsGiven_ThenItIsRunWithTheRemainingInput(t *testing.T) {
+	directory, cleanup := writePlugin(t, "tool-hello", "echo \"$@\"\nexit 3\n")
+	defer cleanup()
+
+	var stdout bytes.Buffer
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionCounter("v", "verbose", "description")
+	parser.AddCommand("build", "description")
+	parser.SetPluginsEnabled(true)
+	parser.SetPluginDirectories(directory)
+	parser.SetOutput(&stdout, nil)
+	code := parser.Run([]string{"-v", "hello", "--name", "world", "-v"})
+
+	actual := stdout.String()
+	if code != 3 || actual != "--name world -v\n" || parser.GetOptionCount("verbose") != 1 {
+		t.Errorf("Expected <3> and <--name world -v>, but got <%d> and <%s>", code, actual)
+	}
+}
+
+func Test_WhenPluginIsSelectedByParseArgs_ThenItIsReturnedWithTheRemainingInput(t *testing.T) {
+	directory, cleanup := writePlugin(t, "tool-hello", "exit 0\n")
+	defer cleanup()
+
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("build", "description")
+	parser.SetPluginsEnabled(true)
+	parser.SetPluginDirectories(directory)
+	err := parser.ParseArgs([]string{"hello", "--name", "world"})
+
+	path, input := parser.GetPlugin()
+	expected := filepath.Join(directory, "tool-hello")
+	if err != nil || path != expected || strings.Join(input, " ") != "--name world" {
+		t.Errorf("Expected <nil>, <%s> and <--name world>, but got <%v>, <%s> and <%v>", expected, err, path, input)
+	}
+}
+
+func Test_WhenNoPluginIsSelected_ThenGetPluginReturnsEmptyPath(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("build", "description")
+	parser.SetPluginsEnabled(true)
+	parser.SetPluginDirectories()
+	parser.ParseArgs([]string{"build"})
+
+	path, input := parser.GetPlugin()
+	if path != "" || len(input) != 0 {
+		t.Errorf("Expected <> and <[]>, but got <%s> and <%v>", path, input)
+	}
+}
+
+func Test_WhenPluginsAreDisabled_ThenUnknownCommandErrorIsReturned(t *testing.T) {
+	directory, cleanup := writePlugin(t, "tool-hello", "exit 0\n")
+	defer cleanup()
+
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("build", "description")
+	parser.SetPluginDirectories(directory)
+	err := parser.ParseArgs([]string{"hello"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.UnknownCommand {
+		t.Errorf("Expected <UnknownCommand>, but got <%v>", err)
+	}
+}
+
+func Test_WhenPluginsAreEnabled_ThenTheyAreListedInTheHelpText(t *testing.T) {
+	directory, cleanup := writePlugin(t, "tool-hello", "exit 0\n")
+	defer cleanup()
+
+	var stdout bytes.Buffer
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionHelp("h", "help", "Show help.")
+	parser.AddCommand("build", "description")
+	parser.SetPluginsEnabled(true)
+	parser.SetPluginDirectories(directory)
+	parser.SetOutput(&stdout, nil)
+	parser.Run([]string{"--help"})
+
+	actual := stdout.String()
+	expected := "Plugins:\n  hello  " + filepath.Join(directory, "tool-hello")
+	if !strings.Contains(actual, expected) {
+		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenCommandAliasIsGiven_ThenTheCommandIsSelected(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("remove", "description", "rm")
+	err := parser.ParseArgs([]string{"rm"})
+
+	actual := parser.GetCommand()
+	if err != nil || actual != "remove" {
+		t.Errorf("Expected <nil> and <remove>, but got <%v> and <%s>", err, actual)
+	}
+}
+
+func Test_WhenAliasClashesWithCommand_ThenPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected <panic>, but got <nil>")
+		}
+	}()
+
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("rm", "description")
+	parser.AddCommand("remove", "description", "rm")
+}
+
+func Test_WhenUniqueCommandPrefixIsGiven_ThenTheCommandIsSelected(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("deploy", "description")
+	parser.AddCommand("status", "description")
+	parser.SetCommandAbbreviationsEnabled(true)
+	err := parser.ParseArgs([]string{"dep"})
+
+	actual := parser.GetCommand()
+	if err != nil || actual != "deploy" {
+		t.Errorf("Expected <nil> and <deploy>, but got <%v> and <%s>", err, actual)
+	}
+}
+
+func Test_WhenAmbiguousCommandPrefixIsGiven_ThenAmbiguousCommandErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("deploy", "description")
+	parser.AddCommand("deps", "description")
+	parser.SetCommandAbbreviationsEnabled(true)
+	err := parser.ParseArgs([]string{"dep"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.AmbiguousCommand || err.Error() != "ambiguous command: dep (deploy, deps)" {
+		t.Errorf("Expected <ambiguous command: dep (deploy, deps)>, but got <%v>", err)
+	}
+}
+
+func Test_WhenCommandPrefixIsAlsoAnExactName_ThenTheExactMatchWins(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("dep", "description")
+	parser.AddCommand("deploy", "description")
+	parser.SetCommandAbbreviationsEnabled(true)
+	err := parser.ParseArgs([]string{"dep"})
+
+	actual := parser.GetCommand()
+	if err != nil || actual != "dep" {
+		t.Errorf("Expected <nil> and <dep>, but got <%v> and <%s>", err, actual)
+	}
+}
+
+func Test_WhenCommandAbbreviationsAreDisabled_ThenPrefixIsAnUnknownCommand(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("deploy", "description")
+	err := parser.ParseArgs([]string{"dep"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.UnknownCommand {
+		t.Errorf("Expected <UnknownCommand>, but got <%v>", err)
+	}
+}
+
+func Test_WhenUniqueLongOptionPrefixIsGiven_ThenTheOptionIsParsed(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionStrict("", "verbose", "description", "")
+	parser.DefineOptionBool("", "color", "description")
+	parser.SetOptionAbbreviationsEnabled(true)
+	err := parser.ParseArgs([]string{"--verb=2", "--no-col"})
+
+	verbose := parser.GetOptionValue("verbose", "")
+	color := parser.GetOptionBoolValue("color", true)
+	if err != nil || verbose != "2" || color {
+		t.Errorf("Expected <nil>, <2> and <false>, but got <%v>, <%s> and <%t>", err, verbose, color)
+	}
+}
+
+func Test_WhenAmbiguousLongOptionPrefixIsGiven_ThenAmbiguousOptionErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionCounter("", "verbose", "description")
+	parser.DefineOptionCounter("", "version", "description")
+	parser.SetOptionAbbreviationsEnabled(true)
+	err := parser.ParseArgs([]string{"--ver"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.AmbiguousOption || err.Error() != "ambiguous option: --ver (--verbose, --version)" {
+		t.Errorf("Expected <ambiguous option: --ver (--verbose, --version)>, but got <%v>", err)
+	}
+}
+
+func Test_WhenLongOptionPrefixIsAlsoAnExactName_ThenTheExactMatchWins(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionCounter("", "all", "description")
+	parser.DefineOptionCounter("", "all-files", "description")
+	parser.SetOptionAbbreviationsEnabled(true)
+	err := parser.ParseArgs([]string{"--all"})
+
+	if err != nil || parser.GetOptionCount("all") != 1 || parser.GetOptionCount("all-files") != 0 {
+		t.Errorf("Expected <nil> and only <--all> counted, but got <%v>", err)
+	}
+}
+
+func Test_WhenOptionAbbreviationsAreDisabled_ThenPrefixIsAnUnknownOption(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionCounter("", "verbose", "description")
+	err := parser.ParseArgs([]string{"--verb"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.UnknownOption {
+		t.Errorf("Expected <UnknownOption>, but got <%v>", err)
+	}
+}
+
+func Test_WhenMisspelledOptionIsGiven_ThenTheClosestOptionIsSuggested(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionCounter("v", "verbose", "description")
+	parser.DefineOptionCounter("", "version", "description")
+	err := parser.ParseArgs([]string{"--verbsoe"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Suggestion != "--verbose" || err.Error() != "unknown option: --verbsoe (did you mean --verbose?)" {
+		t.Errorf("Expected <unknown option: --verbsoe (did you mean --verbose?)>, but got <%v>", err)
+	}
+}
+
+func Test_WhenMisspelledCommandIsGiven_ThenTheClosestCommandIsSuggested(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.AddCommand("build", "description")
+	parser.AddCommand("deploy", "description")
+	err := parser.ParseArgs([]string{"bild"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Suggestion != "build" {
+		t.Errorf("Expected <build>, but got <%v>", err)
+	}
+}
+
+func Test_WhenMisspelledHelpTopicIsGiven_ThenTheClosestTopicIsSuggested(t *testing.T) {
+	var stdout bytes.Buffer
+	parser := newHelpCommandParser(&stdout)
+	err := parser.ParseArgs([]string{"help", "paterns"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Suggestion != "patterns" {
+		t.Errorf("Expected <patterns>, but got <%v>", err)
+	}
+}
+
+func Test_WhenOptionIsGivenWithoutDashes_ThenTheOptionIsSuggested(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionCounter("", "verbose", "description")
+	err := parser.ParseArgs([]string{"verbose"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.UnmatchedValue || parseError.Suggestion != "--verbose" {
+		t.Errorf("Expected <--verbose>, but got <%v>", err)
+	}
+}
+
+func Test_WhenUnknownConfigKeyIsCloseToAnArgumentName_ThenTheArgumentIsSuggested(t *testing.T) {
+	path, cleanup := writeTempFile(t, "app.json", `{"FILS": ["a.txt"]}`)
+	defer cleanup()
+
+	parser := args.NewParser("app", "")
+	parser.DefineOptionStrict("", "config", "description", "")
+	parser.DefineArgumentStrict("FILES", "description", 1, 2, "")
+	parser.SetConfigFile("config", path)
+	err := parser.ParseArgs([]string{})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Suggestion != "FILES" {
+		t.Errorf("Expected <FILES>, but got <%v>", err)
+	}
+}
+
+func Test_WhenNothingIsCloseToTheUnknownOption_ThenNothingIsSuggested(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionCounter("", "verbose", "description")
+	err := parser.ParseArgs([]string{"--output"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Suggestion != "" {
+		t.Errorf("Expected <>, but got <%v>", err)
+	}
+}
+
+func Test_WhenMultiValueArgumentPrecedesRequiredArgument_ThenItLeavesTheValuesTheOtherNeeds(t *testing.T) {
+	parser := args.NewParser("cp", "")
+	parser.DefineArgumentStrict("SOURCE", "description", 1, 10, "")
+	parser.DefineArgumentStrict("DEST", "description", 1, 1, "")
+	err := parser.ParseArgs([]string{"a", "b", "c"})
+
+	sources := strings.Join(parser.GetArgumentValues("SOURCE"), " ")
+	dest := strings.Join(parser.GetArgumentValues("DEST"), " ")
+	if err != nil || sources != "a b" || dest != "c" {
+		t.Errorf("Expected <nil>, <a b> and <c>, but got <%v>, <%s> and <%s>", err, sources, dest)
+	}
+}
+
+func Test_WhenPatternsOverlap_ThenValuesAreMovedToWhereTheyAreNeeded(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineArgumentStrict("NUMBERS", "description", 1, 2, `^\d+$`)
+	parser.DefineArgumentStrict("NAME", "description", 1, 1, "")
+	err := parser.ParseArgs([]string{"1", "2"})
+
+	numbers := strings.Join(parser.GetArgumentValues("NUMBERS"), " ")
+	name := strings.Join(parser.GetArgumentValues("NAME"), " ")
+	if err != nil || numbers != "1" || name != "2" {
+		t.Errorf("Expected <nil>, <1> and <2>, but got <%v>, <%s> and <%s>", err, numbers, name)
+	}
+}
+
+func Test_WhenSeveralAssignmentsAreValid_ThenEarlierArgumentsGetTheEarlierValues(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineArgumentStrict("FIRST", "description", 1, 2, "")
+	parser.DefineArgumentStrict("SECOND", "description", 1, 2, "")
+	err := parser.ParseArgs([]string{"a", "b", "c"})
+
+	first := strings.Join(parser.GetArgumentValues("FIRST"), " ")
+	second := strings.Join(parser.GetArgumentValues("SECOND"), " ")
+	if err != nil || first != "a b" || second != "c" {
+		t.Errorf("Expected <nil>, <a b> and <c>, but got <%v>, <%s> and <%s>", err, first, second)
+	}
+}
+
+func Test_WhenArgumentValuesOverflow_ThenTheFirstUnassignableValueIsReported(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineArgumentStrict("NUMBER", "description", 1, 1, `^\d+$`)
+	parser.DefineArgumentStrict("NAME", "description", 1, 1, "")
+	err := parser.ParseArgs([]string{"1", "a", "b"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.TooManyValues || parseError.Token != "b" || parseError.Index != 2 || parseError.Definition != "NAME" {
+		t.Errorf("Expected <TooManyValues> for <NAME> at <2>, but got <%v>", err)
+	}
+}
+
+func Test_WhenArgumentMinimumCantBeMet_ThenThatArgumentIsReportedMissing(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineArgumentStrict("SOURCE", "description", 1, 2, "")
+	parser.DefineArgumentStrict("DEST", "description", 1, 1, `^/`)
+	err := parser.ParseArgs([]string{"a", "b"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.MissingArgument || parseError.Definition != "DEST" {
+		t.Errorf("Expected <MissingArgument> for <DEST>, but got <%v>", err)
+	}
+}
+
+func Test_WhenCommandsShareConfigFile_ThenKeysOfOtherCommandsAreIgnored(t *testing.T) {
+	path, cleanup := writeTempFile(t, "c.json", `{"region": "eu", "target": "web"}`)
+	defer cleanup()
+
+	parser := args.NewParser("tool", "")
+	parser.SetConfigFile("", path)
+	build := parser.AddCommand("build", "description")
+	build.DefineOptionStrict("", "target", "description", "")
+	deploy := parser.AddCommand("deploy", "description")
+	deploy.DefineOptionStrict("", "region", "description", "")
+	buildErr := parser.ParseArgs([]string{"build"})
+	target := build.GetOptionValue("target", "")
+	deployErr := parser.ParseArgs([]string{"deploy"})
+	region := deploy.GetOptionValue("region", "")
+
+	if buildErr != nil || deployErr != nil || target != "web" || region != "eu" {
+		t.Errorf("Expected <nil>, <nil>, <web> and <eu>, but got <%v>, <%v>, <%s> and <%s>", buildErr, deployErr, target, region)
+	}
+}
+
+func Test_WhenConfigKeyIsDefinedNowhereInTheCommandTree_ThenUnknownOptionErrorIsReturned(t *testing.T) {
+	path, cleanup := writeTempFile(t, "c.json", `{"zone": "a"}`)
+	defer cleanup()
+
+	parser := args.NewParser("tool", "")
+	parser.SetConfigFile("", path)
+	parser.AddCommand("build", "description")
+	parser.AddCommand("deploy", "description").DefineOptionStrict("", "region", "description", "")
+	err := parser.ParseArgs([]string{"build"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.UnknownOption || parseError.Token != "zone" {
+		t.Errorf("Expected <UnknownOption> for <zone>, but got <%v>", err)
+	}
+}
+
+func Test_WhenRepeatableOptionMinimumIsMetByEnvironment_ThenNoErrorIsReturned(t *testing.T) {
+	os.Setenv("TOOL_INCLUDE", "x")
+	defer os.Unsetenv("TOOL_INCLUDE")
+
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionRepeatable("I", "include", "description", 1, 3, "")
+	parser.SetOptionEnvironmentVariable("include", "TOOL_INCLUDE")
+	err := parser.ParseArgs([]string{})
+
+	actual := strings.Join(parser.GetOptionValues("include"), " ")
+	if err != nil || actual != "x" {
+		t.Errorf("Expected <nil> and <x>, but got <%v> and <%s>", err, actual)
+	}
+}
+
+func Test_WhenRepeatableOptionMinimumIsMetByConfigFile_ThenNoErrorIsReturned(t *testing.T) {
+	path, cleanup := writeTempFile(t, "app.json", `{"include": ["a", "b"]}`)
+	defer cleanup()
+
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionRepeatable("I", "include", "description", 2, 3, "")
+	parser.SetConfigFile("", path)
+	err := parser.ParseArgs([]string{})
+
+	if err != nil {
+		t.Errorf("Expected <nil>, but got <%v>", err)
+	}
+}
+
+func Test_WhenRepeatableOptionMinimumIsMetByDefaultValue_ThenNoErrorIsReturned(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionRepeatable("I", "include", "description", 1, 3, "")
+	parser.SetOptionDefaultValue("include", "x")
+	err := parser.ParseArgs([]string{})
+
+	if err != nil {
+		t.Errorf("Expected <nil>, but got <%v>", err)
+	}
+}
+
+func Test_WhenManyArgumentsCantTakeAllValues_ThenTheFirstOverflowingValueIsReported(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	for index := 0; index < 7; index++ {
+		parser.DefineArgumentStrict("ARG"+strconv.Itoa(index), "description", 1, 5, "")
+	}
+	input := make([]string, 40)
+	for index := range input {
+		input[index] = "value" + strconv.Itoa(index)
+	}
+
+	err := parser.ParseArgs(input)
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.TooManyValues || parseError.Index != 35 {
+		t.Errorf("Expected <TooManyValues> at <35>, but got <%v>", err)
+	}
+}
+
+func Test_WhenManyArgumentsShareManyValues_ThenTheEarliestArgumentsGetThem(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	for index := 0; index < 7; index++ {
+		parser.DefineArgumentStrict("ARG"+strconv.Itoa(index), "description", 1, 10, "")
+	}
+	parser.DefineArgumentStrict("NUMBER", "description", 1, 1, `^\d+$`)
+	input := make([]string, 60)
+	for index := range input {
+		input[index] = strconv.Itoa(index)
+	}
+
+	err := parser.ParseArgs(input)
+
+	first := len(parser.GetArgumentValues("ARG0"))
+	number := strings.Join(parser.GetArgumentValues("NUMBER"), " ")
+	if err != nil || first != 10 || number != "59" {
+		t.Errorf("Expected <nil>, <10> and <59>, but got <%v>, <%d> and <%s>", err, first, number)
+	}
+}
+
+func Test_WhenManySourceValuesPrecedeOneDestination_ThenOnlyTheLastValueIsTheDestination(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineArgumentStrict("SOURCE", "description", 1, 100000, "")
+	parser.DefineArgumentStrict("DEST", "description", 1, 1, "")
+	input := make([]string, 20000)
+	for index := range input {
+		input[index] = "file" + strconv.Itoa(index)
+	}
+
+	err := parser.ParseArgs(input)
+
+	sources := parser.GetArgumentValues("SOURCE")
+	dest := strings.Join(parser.GetArgumentValues("DEST"), " ")
+	if err != nil || len(sources) != 19999 || sources[0] != "file0" || sources[19998] != "file19998" || dest != "file19999" {
+		t.Errorf("Expected <nil>, <19999> sources and <file19999>, but got <%v>, <%d> sources and <%s>", err, len(sources), dest)
+	}
+}
+
+func Benchmark_ManyArgumentsCantTakeAllValues(b *testing.B) {
+	input := make([]string, 40)
+	for index := range input {
+		input[index] = "value" + strconv.Itoa(index)
+	}
+
+	for run := 0; run < b.N; run++ {
+		parser := args.NewParser("tool", "")
+		for index := 0; index < 7; index++ {
+			parser.DefineArgumentStrict("ARG"+strconv.Itoa(index), "description", 1, 5, "")
+		}
+		parser.ParseArgs(input)
+	}
+}
+
+func Benchmark_ManyArgumentsShareManyValues(b *testing.B) {
+	input := make([]string, 60)
+	for index := range input {
+		input[index] = strconv.Itoa(index)
+	}
+
+	for run := 0; run < b.N; run++ {
+		parser := args.NewParser("tool", "")
+		for index := 0; index < 7; index++ {
+			parser.DefineArgumentStrict("ARG"+strconv.Itoa(index), "description", 1, 10, "")
+		}
+		parser.DefineArgumentStrict("NUMBER", "description", 1, 1, `^\d+$`)
+		parser.ParseArgs(input)
+	}
+}
+
+func Benchmark_ManySourceValuesPrecedeOneDestination(b *testing.B) {
+	input := make([]string, 20000)
+	for index := range input {
+		input[index] = "file" + strconv.Itoa(index)
+	}
+
+	for run := 0; run < b.N; run++ {
+		parser := args.NewParser("tool", "")
+		parser.DefineArgumentStrict("SOURCE", "description", 1, 100000, "")
+		parser.DefineArgumentStrict("DEST", "description", 1, 1, "")
+		parser.ParseArgs(input)
+	}
+}
+
+func Test_WhenHelpCommandIsGivenItsOwnName_ThenItDescribesItself(t *testing.T) {
+	var stdout bytes.Buffer
+	parser := newHelpCommandParser(&stdout)
+	code := parser.Run([]string{"help", "help"})
+
+	actual := stdout.String()
+	if code != 0 || !strings.HasPrefix(actual, "Usage: tool help") || !strings.Contains(actual, "Show help.") {
+		t.Errorf("Expected <0> and the help command help, but got <%d> and <%s>", code, actual)
+	}
+}
+
+func Test_WhenUnknownNameEqualsACandidate_ThenItIsNotSuggested(t *testing.T) {
+	var stdout bytes.Buffer
+	parser := newHelpCommandParser(&stdout)
+	parser.AddHelpCommand("man", "Show help.")
+	err := parser.ParseArgs([]string{"help", "man"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.UnknownCommand || parseError.Suggestion != "" {
+		t.Errorf("Expected <UnknownCommand> without suggestion, but got <%v>", err)
+	}
+}
+
+func Test_WhenClusterHasCountersBeforeValueOption_ThenTheRestIsTheValue(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionCounter("v", "verbose", "description")
+	parser.DefineOptionStrict("o", "output", "description", "")
+	err := parser.ParseArgs([]string{"-vvofile.txt"})
+
+	count := parser.GetOptionCount("verbose")
+	output := parser.GetOptionValue("output", "")
+	if err != nil || count != 2 || output != "file.txt" {
+		t.Errorf("Expected <nil>, <2> and <file.txt>, but got <%v>, <%d> and <%s>", err, count, output)
+	}
+}
+
+func Test_WhenClusterHasBooleanBeforeValueOption_ThenTheRestIsTheValue(t *testing.T) {
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionBool("c", "color", "description")
+	parser.DefineOptionStrict("o", "output", "description", "")
+	err := parser.ParseArgs([]string{"-cofile.txt"})
+
+	color := parser.GetOptionBoolValue("color", false)
+	output := parser.GetOptionValue("output", "")
+	if err != nil || !color || output != "file.txt" {
+		t.Errorf("Expected <nil>, <true> and <file.txt>, but got <%v>, <%t> and <%s>", err, color, output)
+	}
+}
+
+func Test_WhenINISectionNamesCommand_ThenItsKeysApplyToThatCommand(t *testing.T) {
+	path, cleanup := writeTempFile(t, "a.ini", "region = eu\n\n[deploy]\ntarget = prod\n\n[build]\ntarget = debug\n")
+	defer cleanup()
+
+	parser := args.NewParser("tool", "")
+	parser.DefineOptionStrict("", "region", "description", "")
+	parser.SetConfigFile("", path)
+	build := parser.AddCommand("build", "description")
+	build.DefineOptionStrict("", "target", "description", "")
+	deploy := parser.AddCommand("deploy", "description")
+	deploy.DefineOptionStrict("", "target", "description", "")
+	err := parser.ParseArgs([]string{"deploy"})
+
+	target := deploy.GetOptionValue("target", "")
+	region := deploy.GetOptionValue("region", "")
+	if err != nil || target != "prod" || region != "eu" {
+		t.Errorf("Expected <nil>, <prod> and <eu>, but got <%v>, <%s> and <%s>", err, target, region)
+	}
+}
+
+func Test_WhenINISectionNamesCommandWithoutTheKey_ThenUnknownOptionErrorIsReturned(t *testing.T) {
+	path, cleanup := writeTempFile(t, "a.ini", "[deploy]\nzone = a\n")
+	defer cleanup()
+
+	parser := args.NewParser("tool", "")
+	parser.SetConfigFile("", path)
+	parser.AddCommand("deploy", "description").DefineOptionStrict("", "target", "description", "")
+	err := parser.ParseArgs([]string{"deploy"})
+
+	var parseError *args.ParseError
+	if !errors.As(err, &parseError) || parseError.Kind != args.UnknownOption || parseError.Token != "deploy.zone" {
+		t.Errorf("Expected <UnknownOption> for <deploy.zone>, but got <%v>", err)
+	}
+}
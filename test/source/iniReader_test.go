@@ -0,0 +1,36 @@
+package source_test
+
+import (
+	"testing"
+
+	"github.com/echsylon/go-args/internal/source"
+)
+
+func Test_WhenReadingINIWithSections_ThenKeysArePrefixedWithTheSectionName(t *testing.T) {
+	content := []byte("; comment\nname = app\n\n[server]\nport = 8080\n")
+	entries, err := source.ReadINI(content)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Expected <2> entries, but got <%v> and <%v>", entries, err)
+	}
+
+	if entries[0].Key != "name" || entries[1].Key != "server.port" || entries[1].Line != 5 {
+		t.Errorf("Expected <name> and <server.port> on line <5>, but got <%v>", entries)
+	}
+}
+
+func Test_WhenReadingINIWithRepeatedKey_ThenAllValuesAreCollected(t *testing.T) {
+	content := []byte("include = vendor\ninclude = \"lib\"\n")
+	entries, err := source.ReadINI(content)
+	if err != nil || len(entries) != 1 || len(entries[0].Values) != 2 || entries[0].Values[1] != "lib" {
+		t.Errorf("Expected <1> entry with values <vendor lib>, but got <%v> and <%v>", entries, err)
+	}
+}
+
+func Test_WhenReadingINIWithMissingEqualsSign_ThenSyntaxErrorWithLineIsReturned(t *testing.T) {
+	content := []byte("[server]\nport 8080\n")
+	_, err := source.ReadINI(content)
+	actual, isSyntaxError := err.(*source.SyntaxError)
+	if !isSyntaxError || actual.Line != 2 {
+		t.Errorf("Expected <SyntaxError> on line <2>, but got <%v>", err)
+	}
+}
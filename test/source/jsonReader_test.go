@@ -0,0 +1,69 @@
+package source_test
+
+import (
+	"testing"
+
+	"github.com/echsylon/go-args/internal/source"
+)
+
+func Test_WhenReadingJSONWithScalarValues_ThenEachKeyIsReturnedWithItsValue(t *testing.T) {
+	content := []byte("{\n  \"name\": \"text\",\n  \"port\": 8080,\n  \"color\": true\n}")
+	entries, err := source.ReadJSON(content)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("Expected <3> entries, but got <%d> and <%v>", len(entries), err)
+	}
+
+	actual := entries[0].Values[0] + "," + entries[1].Values[0] + "," + entries[2].Values[0]
+	if actual != "text,8080,true" {
+		t.Errorf("Expected <text,8080,true>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenReadingJSONWithArrayValue_ThenAllValuesAreReturned(t *testing.T) {
+	content := []byte(`{"include": ["vendor", "lib"]}`)
+	entries, err := source.ReadJSON(content)
+	if err != nil || len(entries) != 1 || len(entries[0].Values) != 2 {
+		t.Fatalf("Expected <1> entry with <2> values, but got <%v> and <%v>", entries, err)
+	}
+}
+
+func Test_WhenReadingJSON_ThenTheLineOfEachKeyIsReturned(t *testing.T) {
+	content := []byte("{\n  \"name\": \"text\",\n\n  \"port\": 8080\n}")
+	entries, _ := source.ReadJSON(content)
+	if len(entries) != 2 || entries[0].Line != 2 || entries[1].Line != 4 {
+		t.Errorf("Expected lines <2> and <4>, but got <%v>", entries)
+	}
+}
+
+func Test_WhenReadingJSONWithNestedObject_ThenSyntaxErrorWithLineIsReturned(t *testing.T) {
+	content := []byte("{\n  \"name\": {}\n}")
+	_, err := source.ReadJSON(content)
+	actual, isSyntaxError := err.(*source.SyntaxError)
+	if !isSyntaxError || actual.Line != 2 {
+		t.Errorf("Expected <SyntaxError> on line <2>, but got <%v>", err)
+	}
+}
+
+func Test_WhenReadingMalformedJSON_ThenSyntaxErrorWithLineIsReturned(t *testing.T) {
+	content := []byte("{\n  \"name\": \"text\"\n  \"port\": 8080\n}")
+	_, err := source.ReadJSON(content)
+	actual, isSyntaxError := err.(*source.SyntaxError)
+	if !isSyntaxError || actual.Line != 3 {
+		t.Errorf("Expected <SyntaxError> on line <3>, but got <%v>", err)
+	}
+}
+
+func Test_WhenReadingJSONWithDuplicateKeys_ThenSyntaxErrorIsReturned(t *testing.T) {
+	content := []byte(`{"name": "a", "name": "b"}`)
+	_, err := source.ReadJSON(content)
+	if _, isSyntaxError := err.(*source.SyntaxError); !isSyntaxError {
+		t.Errorf("Expected <SyntaxError>, but got <%v>", err)
+	}
+}
+
+func Test_WhenReadingEmptyJSON_ThenSyntaxErrorIsReturned(t *testing.T) {
+	_, err := source.ReadJSON([]byte(""))
+	if _, isSyntaxError := err.(*source.SyntaxError); !isSyntaxError {
+		t.Errorf("Expected <SyntaxError>, but got <%v>", err)
+	}
+}
@@ -0,0 +1,67 @@
+package source_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/echsylon/go-args/internal/source"
+)
+
+func Test_WhenFindingPlugin_ThenTheFirstExecutableOnTheDirectoryListIsReturned(t *testing.T) {
+	first := createPlugins(t, "tool-foo")
+	second := createPlugins(t, "tool-foo", "tool-bar")
+	defer os.RemoveAll(first)
+	defer os.RemoveAll(second)
+
+	actual, isFound := source.FindPlugin("tool-", "foo", []string{first, second})
+	expected := filepath.Join(first, "tool-foo")
+	if !isFound || actual != expected {
+		t.Errorf("Expected <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenFindingPluginThatIsNotExecutable_ThenNothingIsReturned(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bits are not used on windows")
+	}
+
+	directory := createResponseFiles(t, map[string]string{"tool-foo": "#!/bin/sh\n"})
+	defer os.RemoveAll(directory)
+
+	actual, isFound := source.FindPlugin("tool-", "foo", []string{directory})
+	if isFound {
+		t.Errorf("Expected <false>, but got <true> and <%s>", actual)
+	}
+}
+
+func Test_WhenListingPlugins_ThenShadowedPluginsAreExcludedAndTheRestSorted(t *testing.T) {
+	first := createPlugins(t, "tool-foo", "other-baz")
+	second := createPlugins(t, "tool-foo", "tool-bar")
+	defer os.RemoveAll(first)
+	defer os.RemoveAll(second)
+
+	actual := source.FindPlugins("tool-", []string{first, second})
+	if len(actual) != 2 || actual[0].Name != "bar" || actual[1].Name != "foo" || actual[1].Path != filepath.Join(first, "tool-foo") {
+		t.Errorf("Expected <bar> and <foo> from <%s>, but got <%v>", first, actual)
+	}
+}
+
+func createPlugins(t *testing.T, names ...string) string {
+	files := make(map[string]string)
+	for _, name := range names {
+		if runtime.GOOS == "windows" {
+			name += ".exe"
+		}
+		files[name] = "#!/bin/sh\n"
+	}
+
+	directory := createResponseFiles(t, files)
+	for name := range files {
+		if err := os.Chmod(filepath.Join(directory, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return directory
+}
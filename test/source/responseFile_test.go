@@ -0,0 +1,123 @@
+package source_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/echsylon/go-args/internal/source"
+)
+
+func Test_WhenExpandingResponseFileWithQuotes_ThenShellLikeTokensAreReturned(t *testing.T) {
+	directory := createResponseFiles(t, map[string]string{
+		"args.txt": "-o 'out file.txt' # comment\n\"a \\\"b\\\"\" c\\ d\n",
+	})
+	defer os.RemoveAll(directory)
+
+	tokens, err := source.ExpandResponseFiles([]string{"first", "@" + filepath.Join(directory, "args.txt")}, "--")
+	actual := joinTokenValues(tokens)
+	if err != nil || actual != "first|-o|out file.txt|a \"b\"|c d" {
+		t.Errorf("Expected <first|-o|out file.txt|a \"b\"|c d>, but got <%s> and <%v>", actual, err)
+	}
+}
+
+func Test_WhenExpandingResponseFile_ThenTokensKnowTheirFileAndLine(t *testing.T) {
+	directory := createResponseFiles(t, map[string]string{"args.txt": "-v\n\n--name\n"})
+	defer os.RemoveAll(directory)
+
+	path := filepath.Join(directory, "args.txt")
+	tokens, _ := source.ExpandResponseFiles([]string{"@" + path}, "--")
+	if len(tokens) != 2 || tokens[1].Path != path || tokens[1].Line != 3 || tokens[1].Index != 0 {
+		t.Errorf("Expected <--name> on line <3> of <%s>, but got <%v>", path, tokens)
+	}
+}
+
+func Test_WhenExpandingNestedResponseFiles_ThenAllTokensAreReturnedInOrder(t *testing.T) {
+	directory := createResponseFiles(t, map[string]string{
+		"outer.txt": "a @" + "inner.txt" + " d",
+		"inner.txt": "b c",
+	})
+	defer os.RemoveAll(directory)
+
+	restore := changeDirectory(t, directory)
+	defer restore()
+
+	tokens, err := source.ExpandResponseFiles([]string{"@outer.txt"}, "--")
+	actual := joinTokenValues(tokens)
+	if err != nil || actual != "a|b|c|d" {
+		t.Errorf("Expected <a|b|c|d>, but got <%s> and <%v>", actual, err)
+	}
+}
+
+func Test_WhenExpandingCyclicResponseFiles_ThenExpansionErrorIsReturned(t *testing.T) {
+	directory := createResponseFiles(t, map[string]string{
+		"a.txt": "@b.txt",
+		"b.txt": "x\n@a.txt",
+	})
+	defer os.RemoveAll(directory)
+
+	restore := changeDirectory(t, directory)
+	defer restore()
+
+	_, err := source.ExpandResponseFiles([]string{"@a.txt"}, "--")
+	actual, isExpansionError := err.(*source.ExpansionError)
+	if !isExpansionError || actual.Path != "b.txt" || actual.Line != 2 || !strings.Contains(actual.Message, "a.txt -> b.txt -> a.txt") {
+		t.Errorf("Expected <cycle> at <b.txt:2>, but got <%v>", err)
+	}
+}
+
+func Test_WhenExpandingResponseFileWithUnterminatedQuote_ThenTheLineIsReported(t *testing.T) {
+	directory := createResponseFiles(t, map[string]string{"args.txt": "-v\n'open\n"})
+	defer os.RemoveAll(directory)
+
+	path := filepath.Join(directory, "args.txt")
+	_, err := source.ExpandResponseFiles([]string{"@" + path}, "--")
+	actual, isExpansionError := err.(*source.ExpansionError)
+	if !isExpansionError || actual.Path != path || actual.Line != 2 {
+		t.Errorf("Expected <error> at <%s:2>, but got <%v>", path, err)
+	}
+}
+
+func Test_WhenExpandingAfterEndOfOptionsToken_ThenTokensAreKeptLiterally(t *testing.T) {
+	tokens, err := source.ExpandResponseFiles([]string{"--", "@missing.txt"}, "--")
+	actual := joinTokenValues(tokens)
+	if err != nil || actual != "--|@missing.txt" {
+		t.Errorf("Expected <--|@missing.txt>, but got <%s> and <%v>", actual, err)
+	}
+}
+
+func createResponseFiles(t *testing.T, files map[string]string) string {
+	directory, err := ioutil.TempDir("", "go-args")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(directory, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return directory
+}
+
+func changeDirectory(t *testing.T, directory string) func() {
+	current, err := os.Getwd()
+	if err == nil {
+		err = os.Chdir(directory)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func() { os.Chdir(current) }
+}
+
+func joinTokenValues(tokens []source.Token) string {
+	var values []string
+	for _, token := range tokens {
+		values = append(values, token.Value)
+	}
+	return strings.Join(values, "|")
+}
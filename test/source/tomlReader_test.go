@@ -0,0 +1,71 @@
+package source_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/echsylon/go-args/internal/source"
+)
+
+func Test_WhenReadingTOMLWithScalarValues_ThenEachKeyIsReturnedWithItsValue(t *testing.T) {
+	content := []byte("name = \"a\\tb\" # comment\npath = 'C:\\dir'\nport = 8_080\nratio = 0.5\ncolor = true\n")
+	entries, err := source.ReadTOML(content)
+	if err != nil || len(entries) != 5 {
+		t.Fatalf("Expected <5> entries, but got <%v> and <%v>", entries, err)
+	}
+
+	var values []string
+	for _, entry := range entries {
+		values = append(values, entry.Values[0])
+	}
+	actual := strings.Join(values, ",")
+	if actual != "a\tb,C:\\dir,8080,0.5,true" {
+		t.Errorf("Expected <a\tb,C:\\dir,8080,0.5,true>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenReadingTOMLWithTablesAndDottedKeys_ThenKeysArePrefixed(t *testing.T) {
+	content := []byte("[server]\nport = 8080\ntls.cert = \"cert.pem\"\n\n[\"log\"]\nlevel = \"debug\"\n")
+	entries, err := source.ReadTOML(content)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("Expected <3> entries, but got <%v> and <%v>", entries, err)
+	}
+
+	actual := entries[0].Key + "," + entries[1].Key + "," + entries[2].Key
+	if actual != "server.port,server.tls.cert,log.level" || entries[2].Line != 6 {
+		t.Errorf("Expected <server.port,server.tls.cert,log.level> and line <6>, but got <%s> and <%d>", actual, entries[2].Line)
+	}
+}
+
+func Test_WhenReadingTOMLWithMultiLineArray_ThenAllValuesAreReturned(t *testing.T) {
+	content := []byte("include = [\n  \"vendor\", # first\n  \"lib\",\n]\nport = 1\n")
+	entries, err := source.ReadTOML(content)
+	if err != nil || len(entries) != 2 || len(entries[0].Values) != 2 || entries[1].Line != 5 {
+		t.Errorf("Expected <2> entries with <2> values first and the second on line <5>, but got <%v> and <%v>", entries, err)
+	}
+}
+
+func Test_WhenReadingTOMLWithUnsupportedValue_ThenSyntaxErrorWithLineIsReturned(t *testing.T) {
+	content := []byte("name = \"app\"\nwhen = 1979-05-27\n")
+	_, err := source.ReadTOML(content)
+	actual, isSyntaxError := err.(*source.SyntaxError)
+	if !isSyntaxError || actual.Line != 2 {
+		t.Errorf("Expected <SyntaxError> on line <2>, but got <%v>", err)
+	}
+}
+
+func Test_WhenReadingTOMLWithDuplicateKeys_ThenSyntaxErrorIsReturned(t *testing.T) {
+	content := []byte("name = \"a\"\nname = \"b\"\n")
+	_, err := source.ReadTOML(content)
+	if _, isSyntaxError := err.(*source.SyntaxError); !isSyntaxError {
+		t.Errorf("Expected <SyntaxError>, but got <%v>", err)
+	}
+}
+
+func Test_WhenReadingTOMLWithMissingLineBreak_ThenSyntaxErrorIsReturned(t *testing.T) {
+	content := []byte("name = \"a\" port = 1\n")
+	_, err := source.ReadTOML(content)
+	if _, isSyntaxError := err.(*source.SyntaxError); !isSyntaxError {
+		t.Errorf("Expected <SyntaxError>, but got <%v>", err)
+	}
+}
@@ -0,0 +1,31 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/echsylon/go-args/internal/model"
+	"github.com/echsylon/go-args/internal/util"
+)
+
+func Test_WhenComposingValuesDump_ThenEachValueIsListedWithItsSource(t *testing.T) {
+	expected := "--include  vendor  (command line, index 1)\n" +
+		"           lib     (environment variable INCLUDE)\n" +
+		"FILE       a.txt   (default value)"
+	records := []util.ValueRecord{
+		{Name: "--include", Values: []string{"vendor", "lib"}, Sources: []model.Source{model.NewCommandLineSource(1), model.NewEnvironmentSource("INCLUDE")}},
+		{Name: "FILE", Values: []string{"a.txt"}, Sources: []model.Source{model.NewDefaultSource()}},
+	}
+	actual := util.GetValuesDump(records)
+	if actual != expected {
+		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenComposingValuesDumpWithoutValues_ThenTheRecordIsNotGiven(t *testing.T) {
+	expected := "--debug  (not given)"
+	records := []util.ValueRecord{{Name: "--debug"}}
+	actual := util.GetValuesDump(records)
+	if actual != expected {
+		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
+	}
+}
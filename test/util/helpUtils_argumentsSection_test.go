@@ -39,3 +39,14 @@ func Test_WhenComposingArgumentsHelpSectionWithMultipleArguments_ThenEachArgumen
 		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
 	}
 }
+
+func Test_WhenComposingArgumentsHelpSectionWithDefaultValues_ThenTheDefaultValuesAreIncluded(t *testing.T) {
+	expected := "Arguments:\n  FILES  Files to read. (default: a.txt, b.txt)"
+	argument := model.NewArgument("FILES", "Files to read.", 1, 2, "")
+	argument.SetDefaultValues([]string{"a.txt", "b.txt"})
+	arguments := []model.Argument{argument}
+	actual := util.GetArgumentsHelpSection(&arguments)
+	if actual != expected {
+		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
+	}
+}
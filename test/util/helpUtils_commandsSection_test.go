@@ -0,0 +1,47 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/echsylon/go-args/internal/model"
+	"github.com/echsylon/go-args/internal/util"
+)
+
+type command struct {
+	name        string
+	description string
+	aliases     []string
+}
+
+func (command *command) GetName() string        { return command.name }
+func (command *command) GetDescription() string { return command.description }
+func (command *command) GetAliases() []string   { return command.aliases }
+
+func newCommand(name string, description string, aliases ...string) model.Command {
+	return &command{name: name, description: description, aliases: aliases}
+}
+
+func Test_WhenComposingCommandsHelpSectionWithNoCommands_ThenEmptyStringIsReturned(t *testing.T) {
+	actual := util.GetCommandsHelpSection(nil)
+	if actual != "" {
+		t.Errorf("Expected: <>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenComposingCommandsHelpSectionWithMultipleCommands_ThenEachCommandIsIncludedOnItsOwnRow(t *testing.T) {
+	expected := "Commands:\n  build   Build it.\n  deploy  Deploy it."
+	commands := []model.Command{newCommand("build", "Build it."), newCommand("deploy", "Deploy it.")}
+	actual := util.GetCommandsHelpSection(&commands)
+	if actual != expected {
+		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenComposingCommandsHelpSectionWithAliases_ThenTheAliasesFollowTheName(t *testing.T) {
+	expected := "Commands:\n  build       Build it.\n  remove, rm  Remove it."
+	commands := []model.Command{newCommand("build", "Build it."), newCommand("remove", "Remove it.", "rm")}
+	actual := util.GetCommandsHelpSection(&commands)
+	if actual != expected {
+		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
+	}
+}
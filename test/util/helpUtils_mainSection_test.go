@@ -12,7 +12,7 @@ func Test_WhenComposingMainHelpSectionWithoutOptionsAndArguments_ThenOnlyTheAppN
 	appName := "app"
 	appDescr := "description"
 	expected := fmt.Sprintf("Usage: %s\n%s", appName, appDescr)
-	actual := util.GetMainHelpSection(appName, appDescr, nil, nil)
+	actual := util.GetMainHelpSection(appName, appDescr, nil, nil, nil)
 	if actual != expected {
 		t.Errorf("Expected:\n<%s>, but got\n<%s>", expected, actual)
 	}
@@ -23,7 +23,7 @@ func Test_WhenComposingMainHelpSectionWithSingleOption_ThenOptionNotationIsInclu
 	appDescr := "description"
 	options := []model.Option{model.NewOption("n", "name", "descr", "")}
 	expected := fmt.Sprintf("Usage: %s [OPTION]\n%s", appName, appDescr)
-	actual := util.GetMainHelpSection(appName, appDescr, &options, nil)
+	actual := util.GetMainHelpSection(appName, appDescr, &options, nil, nil)
 	if actual != expected {
 		t.Errorf("Expected:\n<%s>, but got\n<%s>", expected, actual)
 	}
@@ -36,7 +36,7 @@ func Test_WhenComposingMainHelpSectionWithMultipleOptions_ThenOptionsNotationWit
 		model.NewOption("n", "name", "descr", ""),
 		model.NewOption("", "other", "descr", "")}
 	expected := fmt.Sprintf("Usage: %s [OPTIONS...]\n%s", appName, appDescr)
-	actual := util.GetMainHelpSection(appName, appDescr, &options, nil)
+	actual := util.GetMainHelpSection(appName, appDescr, &options, nil, nil)
 	if actual != expected {
 		t.Errorf("Expected:\n<%s>, but got\n<%s>", expected, actual)
 	}
@@ -48,7 +48,7 @@ func Test_WhenComposingMainHelpSectionWithSingleValueArgument_ThenArgumentNameIs
 	argName := "ARG"
 	arguments := []model.Argument{model.NewArgument(argName, "descr", 1, 1, "")}
 	expected := fmt.Sprintf("Usage: %s %s\n%s", appName, argName, appDescr)
-	actual := util.GetMainHelpSection("app", "description", nil, &arguments)
+	actual := util.GetMainHelpSection("app", "description", nil, &arguments, nil)
 	if actual != expected {
 		t.Errorf("Expected:\n<%s>, but got\n<%s>", expected, actual)
 	}
@@ -60,7 +60,7 @@ func Test_WhenComposingMainHelpSectionWithMultiValueArgument_ThenArgumentNameWit
 	argName := "ARG"
 	arguments := []model.Argument{model.NewArgument(argName, "descr", 1, 2, "")}
 	expected := fmt.Sprintf("Usage: %s %s...\n%s", appName, argName, appDescr)
-	actual := util.GetMainHelpSection(appName, appDescr, nil, &arguments)
+	actual := util.GetMainHelpSection(appName, appDescr, nil, &arguments, nil)
 	if actual != expected {
 		t.Errorf("Expected:\n<%s>, but got\n<%s>", expected, actual)
 	}
@@ -75,7 +75,7 @@ func Test_WhenComposingMainHelpSectionWithTwoArguments_ThenBothArgumentNamesAreI
 		model.NewArgument(argName1, "descr", 1, 1, ""),
 		model.NewArgument(argName2, "descr", 1, 1, "")}
 	expected := fmt.Sprintf("Usage: %s %s %s\n%s", appName, argName1, argName2, appDescr)
-	actual := util.GetMainHelpSection("app", "description", nil, &arguments)
+	actual := util.GetMainHelpSection("app", "description", nil, &arguments, nil)
 	if actual != expected {
 		t.Errorf("Expected:\n<%s>, but got\n<%s>", expected, actual)
 	}
@@ -88,8 +88,42 @@ func Test_WhenComposingMainHelpSectionWithOptionsAndArguments_ThenBothOptionNota
 	options := []model.Option{model.NewOption("n", "name", "descr", "")}
 	arguments := []model.Argument{model.NewArgument(argName, "descr", 1, 1, "")}
 	expected := fmt.Sprintf("Usage: %s [OPTION] %s\n%s", appName, argName, appDescr)
-	actual := util.GetMainHelpSection("app", "description", &options, &arguments)
+	actual := util.GetMainHelpSection("app", "description", &options, &arguments, nil)
 	if actual != expected {
 		t.Errorf("Expected:\n<%s>, but got\n<%s>", expected, actual)
 	}
 }
+
+func Test_WhenComposingMainHelpSectionWithDefaultedArgument_ThenArgumentNameIsBracketed(t *testing.T) {
+	argument := model.NewArgument("ARG", "descr", 1, 2, "")
+	argument.SetDefaultValues([]string{"value"})
+	arguments := []model.Argument{argument}
+	expected := "Usage: app [ARG...]\ndescription"
+	actual := util.GetMainHelpSection("app", "description", nil, &arguments, nil)
+	if actual != expected {
+		t.Errorf("Expected:\n<%s>, but got\n<%s>", expected, actual)
+	}
+}
+
+func Test_WhenComposingMainHelpSectionWithRequiredOption_ThenItIsListedByName(t *testing.T) {
+	required := model.NewOption("r", "region", "descr", "")
+	required.SetRequired()
+	options := []model.Option{
+		model.NewOption("n", "name", "descr", ""),
+		required,
+		model.NewOption("q", "", "descr", "")}
+	expected := "Usage: app [OPTIONS...] --region\ndescription"
+	actual := util.GetMainHelpSection("app", "description", &options, nil, nil)
+	if actual != expected {
+		t.Errorf("Expected:\n<%s>, but got\n<%s>", expected, actual)
+	}
+}
+
+func Test_WhenComposingMainHelpSectionWithCommands_ThenTheCommandPlaceholderIsIncluded(t *testing.T) {
+	expected := "Usage: tool COMMAND"
+	commands := []model.Command{newCommand("build", "Build it.")}
+	actual := util.GetMainHelpSection("tool", "", nil, nil, &commands)
+	if actual != expected {
+		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
+	}
+}
@@ -68,3 +68,71 @@ func Test_WhenComposingOptionsHelpSectionWithMultipleOptions_ThenEachOptionIsInc
 		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
 	}
 }
+
+func Test_WhenComposingOptionsHelpSectionWithRepeatableOptions_ThenEllipsisIsIncluded(t *testing.T) {
+	var stringBuilder strings.Builder
+	stringBuilder.WriteString("Options:\n")
+	stringBuilder.WriteString("  -v, --verbose...  Verbosity description\n")
+	stringBuilder.WriteString("  -I...             Include description\n")
+	stringBuilder.WriteString("  -n, --name        Name description")
+	expected := stringBuilder.String()
+	options := []model.Option{
+		model.NewCounterOption("v", "verbose", "Verbosity description"),
+		model.NewRepeatableOption("I", "", "Include description", 0, 2, ""),
+		model.NewOption("n", "name", "Name description", ""),
+	}
+	actual := util.GetOptionsHelpSection(&options)
+	if actual != expected {
+		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenComposingOptionsHelpSectionWithBooleanOption_ThenNegationIsIncluded(t *testing.T) {
+	var stringBuilder strings.Builder
+	stringBuilder.WriteString("Options:\n")
+	stringBuilder.WriteString("  -c, --[no-]color  Color description\n")
+	stringBuilder.WriteString("  -n, --name        Name description")
+	expected := stringBuilder.String()
+	options := []model.Option{
+		model.NewBooleanOption("c", "color", "Color description"),
+		model.NewOption("n", "name", "Name description", ""),
+	}
+	actual := util.GetOptionsHelpSection(&options)
+	if actual != expected {
+		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenComposingOptionsHelpSectionWithDefaultValue_ThenTheDefaultValueIsIncluded(t *testing.T) {
+	expected := "Options:\n  -m, --max  Max lines. (default: 2)"
+	option := model.NewOption("m", "max", "Max lines.", "")
+	option.SetDefaultValue("2")
+	options := []model.Option{option}
+	actual := util.GetOptionsHelpSection(&options)
+	if actual != expected {
+		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenComposingOptionsHelpSectionWithRequiredOption_ThenItIsMarkedAsRequired(t *testing.T) {
+	expected := "Options:\n  -r, --region  Region. (required)"
+	option := model.NewOption("r", "region", "Region.", "")
+	option.SetRequired()
+	options := []model.Option{option}
+	actual := util.GetOptionsHelpSection(&options)
+	if actual != expected {
+		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
+	}
+}
+
+func Test_WhenComposingOptionsHelpSectionWithEnvironmentVariable_ThenTheVariableIsIncluded(t *testing.T) {
+	expected := "Options:\n  -p, --port  Port. (env: APP_PORT)"
+	option := model.NewOption("p", "port", "Port.", "")
+	option.SetEnvironmentVariable("PORT")
+	option.SetEnvironmentPrefix("APP_")
+	options := []model.Option{option}
+	actual := util.GetOptionsHelpSection(&options)
+	if actual != expected {
+		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
+	}
+}
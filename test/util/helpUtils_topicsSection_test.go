@@ -0,0 +1,27 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/echsylon/go-args/internal/model"
+	"github.com/echsylon/go-args/internal/util"
+)
+
+func Test_WhenComposingTopicsHelpSectionWithNoTopics_ThenEmptyStringIsReturned(t *testing.T) {
+	actual := util.GetTopicsHelpSection(nil)
+	if actual != "" {
+		t.Errorf("Expected: <>, but got <%s>", actual)
+	}
+}
+
+func Test_WhenComposingTopicsHelpSectionWithMultipleTopics_ThenEachTopicIsIncludedOnItsOwnRow(t *testing.T) {
+	expected := "Help topics:\n  env       Environment variables.\n  patterns  Value patterns."
+	topics := []model.Topic{
+		model.NewTopic("env", "Environment variables.", "text"),
+		model.NewTopic("patterns", "Value patterns.", "text"),
+	}
+	actual := util.GetTopicsHelpSection(&topics)
+	if actual != expected {
+		t.Errorf("Expected: <%s>, but got <%s>", expected, actual)
+	}
+}